@@ -1,58 +1,334 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
+	"time"
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/config"
+	"web-analyzer/internal/export"
+	"web-analyzer/internal/grpcserver"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/netguard"
+	"web-analyzer/internal/ratelimit"
+	"web-analyzer/internal/scheduler"
 	"web-analyzer/internal/server"
+	"web-analyzer/internal/storage"
+	"web-analyzer/internal/utils"
+	"web-analyzer/internal/webhook"
 )
 
 func main() {
-	var (
-		port        = flag.Int("port", 8080, "Port for the HTTP server")
-		debugPort   = flag.Int("debug-port", 6060, "Debug server port for pprof")
-		logLevel    = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
-		concurrency = flag.Int("concurrency", runtime.NumCPU(), "Maximum concurrency level")
-	)
-	flag.Parse()
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		os.Exit(analyzeCommand(os.Args[2:]))
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	logLevel := new(slog.LevelVar)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})))
+
+	if err := applyReloadable(cfg, logLevel); err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	runtime.GOMAXPROCS(cfg.Server.Concurrency)
+
+	if cfg.Server.DebugPort > 0 {
+		go startDebugServer(cfg.Server.DebugPort)
+	}
+
+	routerOpts := server.Options{
+		CORSOrigins:    cfg.Server.CORSOrigins,
+		CORSMaxAge:     time.Duration(cfg.Server.CORSMaxAge),
+		TrustedProxies: cfg.Server.TrustedProxies,
+	}
+	if cfg.Server.AuthConfig != "" {
+		authCfg, err := auth.LoadConfig(cfg.Server.AuthConfig)
+		if err != nil {
+			slog.Error("failed to load auth config", "error", err)
+			os.Exit(1)
+		}
+		routerOpts.Auth = auth.New(authCfg)
+		slog.Info("API key authentication enabled", "keys", len(authCfg.Keys))
+	}
+
+	if cfg.Server.RateLimit > 0 {
+		policy := ratelimit.Policy{Rate: cfg.Server.RateLimit, Burst: cfg.Server.RateBurst}
+		routerOpts.RateLimiters = map[string]ratelimit.Limiter{
+			server.RouteGroupAPI:    ratelimit.NewMemory(policy),
+			server.RouteGroupLegacy: ratelimit.NewMemory(policy),
+		}
+	}
+
+	store, err := openResultStore(cfg.Storage)
+	if err != nil {
+		slog.Error("failed to open result store", "error", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+	analysis.SetResultStore(store)
+
+	if cfg.Cache.MaxEntries > 0 {
+		analysis.SetResultCache(analysis.NewResultCache(analysis.CacheConfig{
+			MaxEntries: cfg.Cache.MaxEntries,
+			DefaultTTL: time.Duration(cfg.Cache.DefaultTTL),
+			MaxAge:     time.Duration(cfg.Cache.MaxAge),
+		}))
+	}
+
+	sender := newWebhookSender(cfg.Webhook)
+	schedOpts := scheduler.Options{
+		Analyze:       analysis.AnalyzePage,
+		Store:         store,
+		Sender:        sender,
+		JobsPath:      cfg.Schedule.JobsPath,
+		MaxConcurrent: cfg.Schedule.MaxConcurrent,
+		RunTimeout:    time.Duration(cfg.Schedule.RunTimeout),
+	}
+	if routerOpts.Auth != nil {
+		schedOpts.ChargeQuota = func(keyID string, n int) error {
+			_, err := routerOpts.Auth.ChargeQuota(keyID, n)
+			return err
+		}
+	}
+	sched, err := scheduler.New(schedOpts)
+	if err != nil {
+		slog.Error("failed to load schedules", "error", err)
+		os.Exit(1)
+	}
+	routerOpts.Scheduler = sched
+	routerOpts.Deliveries = sender.Log
+	analysis.SetCallbackSender(sender)
+
+	ctx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go sched.Run(ctx)
+
+	go reloadOnSIGHUP(cfg, logLevel)
+
+	if cfg.Server.GRPCPort > 0 {
+		// gRPC calls share the REST API group's limiter, so a client has one budget.
+		grpcServer := grpcserver.New(grpcserver.Options{
+			Auth:        routerOpts.Auth,
+			RateLimiter: routerOpts.RateLimiters[server.RouteGroupAPI],
+		})
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPCPort))
+		if err != nil {
+			slog.Error("failed to listen for gRPC", "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				slog.Error("gRPC server failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout))
+			defer cancel()
+			grpcServer.Shutdown(ctx)
+		}()
+	}
+
+	router := server.SetupRouterWithOptions(routerOpts)
+	addr := fmt.Sprintf(":%d", cfg.Server.Port)
 
-	logger := setupLogger(*logLevel)
-	slog.SetDefault(logger)
+	slog.Info("starting web analyzer ", "port", cfg.Server.Port, "grpc_port", cfg.Server.GRPCPort, "debug_port", cfg.Server.DebugPort,
+		"log_level", cfg.Log.Level, "concurrency", cfg.Server.Concurrency, "go_version", runtime.Version())
 
-	runtime.GOMAXPROCS(*concurrency)
+	server.RunServer(router, addr, time.Duration(cfg.Server.ShutdownTimeout))
+}
+
+// applyReloadable applies the settings that can change while the server is running:
+// the log level, the outbound network policy and transport, and the analysis tunables.
+func applyReloadable(cfg *config.Config, logLevel *slog.LevelVar) error {
+	guard, err := netguard.New(cfg.Network.AllowCIDRs, cfg.Network.DenyCIDRs)
+	if err != nil {
+		return err
+	}
+	if err := logLevel.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+		return err
+	}
+	utils.SetNetworkGuard(guard)
+
+	n := cfg.Network
+	utils.SetTransportSettings(utils.TransportSettings{
+		MaxIdleConns:          n.MaxIdleConns,
+		MaxIdleConnsPerHost:   n.MaxIdleConnsPerHost,
+		IdleConnTimeout:       time.Duration(n.IdleConnTimeout),
+		DialTimeout:           time.Duration(n.DialTimeout),
+		TLSHandshakeTimeout:   time.Duration(n.TLSHandshakeTimeout),
+		ResponseHeaderTimeout: time.Duration(n.ResponseHeaderTimeout),
+		DisableHTTP2:          n.DisableHTTP2,
+	})
+
+	a := cfg.Analysis
+	analysis.Configure(analysis.Settings{
+		Timeout:      time.Duration(a.Timeout),
+		FetchTimeout: time.Duration(a.FetchTimeout),
+		Workers:      a.Workers,
+		LinkBuffer:   a.LinkBuffer,
+		UserAgent:    a.UserAgent,
+		LinkChecker: utils.LinkChecker{
+			Timeout:      time.Duration(a.LinkTimeout),
+			MaxRedirects: a.MaxRedirects,
+			UserAgent:    a.UserAgent,
+		},
+		Limits: models.ParseLimits{
+			MaxBodyBytes: a.MaxBodyBytes,
+			MaxNodes:     a.MaxDOMNodes,
+			MaxLinks:     a.MaxLinks,
+			MaxDepth:     a.MaxDepth,
+		},
+	})
+	return nil
+}
+
+// analyzeCommand runs "web-analyzer analyze [-format f] [-o file] [-config file] [-any-status] URL": it
+// analyzes one page with the server's configuration and prints the result in format f.
+// The exit status is 0 on success, 1 if the analysis failed and 2 for usage errors.
+func analyzeCommand(args []string) int {
+	fs := flag.NewFlagSet("web-analyzer analyze", flag.ContinueOnError)
+	formatName := fs.String("format", string(export.JSON), "Output format: "+export.FormatNames())
+	output := fs.String("o", "", "Write the result to this file instead of stdout")
+	configPath := fs.String("config", os.Getenv(config.EnvPrefix+"CONFIG"), "Path to a YAML or TOML config file")
+	anyStatus := fs.Bool("any-status", false, "Analyze HTML pages answered with an error status instead of failing")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: web-analyzer analyze [flags] URL")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var configArgs []string
+	if *configPath != "" {
+		configArgs = []string{"-config", *configPath}
+	}
+	cfg, err := config.Load(configArgs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	logLevel := new(slog.LevelVar)
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel})))
+	if err := applyReloadable(cfg, logLevel); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid configuration:", err)
+		return 2
+	}
 
-	go startDebugServer(*debugPort)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	targetURL := fs.Arg(0)
+	analyzedAt := time.Now()
+	result, err := analysis.AnalyzePageWithOptions(ctx, targetURL, models.AnalysisOptions{AnyStatus: *anyStatus})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "analysis failed:", err)
+		return 1
+	}
 
-	router := server.SetupRouter()
-	addr := fmt.Sprintf(":%d", *port)
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer out.Close()
+	}
+	report := export.Report{URL: targetURL, AnalyzedAt: analyzedAt, Analysis: result}
+	if err := export.Write(out, format, report); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to write result:", err)
+		return 1
+	}
+	return 0
+}
 
-	slog.Info("starting web analyzer ", "port", *port, "debug_port", *debugPort,
-		"log_level", *logLevel, "concurrency", *concurrency, "go_version", runtime.Version())
+func newWebhookSender(cfg config.WebhookConfig) *webhook.Sender {
+	if cfg.Secret == "" {
+		slog.Warn("webhook secret is not set, webhook signatures cannot be trusted")
+	}
+	policy := webhook.RetryPolicy{
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: time.Duration(cfg.InitialBackoff),
+		MaxBackoff:     time.Duration(cfg.MaxBackoff),
+	}
+	sender := webhook.NewSender(cfg.Secret, policy, time.Duration(cfg.Timeout))
+	sender.Log = webhook.NewDeliveryLog(cfg.LogSize)
+	return sender
+}
 
-	server.RunServer(router, addr)
+func openResultStore(cfg config.StorageConfig) (storage.Store, error) {
+	retention := storage.Retention{
+		MaxAge:     time.Duration(cfg.MaxAge),
+		MaxPerURL:  cfg.MaxPerURL,
+		MaxRecords: cfg.MaxRecords,
+	}
+	if cfg.Path == "" {
+		slog.Info("storing analysis results in memory")
+		return storage.NewMemoryStore(retention), nil
+	}
+	slog.Info("storing analysis results on disk", "path", cfg.Path)
+	return storage.OpenFileStore(cfg.Path, retention)
 }
 
-func setupLogger(levelStr string) *slog.Logger {
-	var level slog.Level
-	switch levelStr {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
-	}
-
-	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
-	return slog.New(handler)
+// reloadOnSIGHUP reloads the configuration from the same file, environment and flags on
+// every SIGHUP. An invalid configuration is logged and the running one is kept.
+func reloadOnSIGHUP(current *config.Config, logLevel *slog.LevelVar) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	reloader := config.NewReloader(current, func(next *config.Config) error {
+		return applyReloadable(next, logLevel)
+	})
+	for range hup {
+		next, err := config.Load(os.Args[1:])
+		if err != nil {
+			slog.Error("config reload failed, keeping the running configuration", "error", err)
+			continue
+		}
+		pending, err := reloader.Reload(next)
+		if err != nil {
+			slog.Error("config reload failed, keeping the running configuration", "error", err)
+			continue
+		}
+		if len(pending) > 0 {
+			slog.Warn("config changes need a restart to take effect", "settings", pending)
+		}
+		slog.Info("configuration reloaded", "log_level", next.Log.Level)
+	}
 }
 
 func startDebugServer(port int) {
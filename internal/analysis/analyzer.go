@@ -2,122 +2,275 @@ package analysis
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
 	"net/url"
 	"runtime"
-	"sync"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/net/html"
 	"log/slog"
 
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/export"
 	"web-analyzer/internal/models"
 	"web-analyzer/internal/utils"
 	"web-analyzer/pkg/metrics"
 )
 
+// Settings are the process-wide analysis tunables.
+type Settings struct {
+	Timeout      time.Duration // whole analysis, including link checks
+	FetchTimeout time.Duration // HTTP client timeout for the analyzed page
+	Workers      int           // concurrent link checks per analysis
+	LinkBuffer   int           // links queued between the parser and the link checkers
+	UserAgent    string
+	LinkChecker  utils.LinkChecker
+	// Limits bound every analysis unless the caller's options set a limit explicitly.
+	Limits models.ParseLimits
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		Timeout:      30 * time.Second,
+		FetchTimeout: 10 * time.Second,
+		Workers:      runtime.NumCPU() * 2,
+		LinkBuffer:   100,
+		UserAgent:    utils.DefaultUserAgent,
+		LinkChecker:  utils.DefaultLinkChecker(),
+		Limits: models.ParseLimits{
+			MaxBodyBytes: 10 << 20,
+			MaxNodes:     200_000,
+			MaxLinks:     5_000,
+			MaxDepth:     512,
+		},
+	}
+}
+
+var settings atomic.Pointer[Settings]
+
+func init() {
+	s := DefaultSettings()
+	settings.Store(&s)
+}
+
+// Configure replaces the settings used by analyses started from now on.
+func Configure(s Settings) {
+	settings.Store(&s)
+}
+
+// CurrentSettings returns the settings new analyses use.
+func CurrentSettings() Settings {
+	return *settings.Load()
+}
+
 func AnalyzePage(ctx context.Context, targetURL string) (*models.PageAnalysis, error) {
+	return AnalyzePageWithOptions(ctx, targetURL, models.AnalysisOptions{})
+}
+
+func AnalyzePageWithOptions(ctx context.Context, targetURL string, opts models.AnalysisOptions) (*models.PageAnalysis, error) {
+	return analyzePage(ctx, targetURL, opts, nil, nil)
+}
+
+// errNotModified is returned by analyzePage when a conditional page fetch got 304.
+var errNotModified = errors.New("page not modified")
+
+// pageFetch carries the cache validators sent with the page request and the response
+// headers the result cache needs. Nil disables both.
+type pageFetch struct {
+	etag         string // sent as If-None-Match
+	lastModified string // sent as If-Modified-Since
+	header       http.Header
+}
+
+// analyzePage analyzes targetURL. onLink, when not nil, is called with the outcome of
+// each link check as it completes, one call at a time and never after analyzePage returned.
+func analyzePage(ctx context.Context, targetURL string, opts models.AnalysisOptions, fetch *pageFetch,
+	onLink func(LinkResult)) (*models.PageAnalysis, error) {
 
 	if err := validateURL(targetURL); err != nil {
 		return nil, err
 	}
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+	cfg := CurrentSettings()
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	filter, err := utils.NewLinkFilter(opts.IncludeLinks, opts.ExcludeLinks)
+	if err != nil {
+		return nil, newError(ErrInvalidInput, err, "invalid link filter")
+	}
+
+	userAgent := cfg.UserAgent
+	checker := cfg.LinkChecker
+	workers := cfg.Workers
+	if opts.UserAgent != "" {
+		userAgent = opts.UserAgent
+		checker.UserAgent = opts.UserAgent
+	}
+	if opts.LinkTimeout > 0 {
+		checker.Timeout = opts.LinkTimeout
+	}
+	if opts.LinkConcurrency > 0 {
+		workers = opts.LinkConcurrency
 	}
 
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	client := utils.NewHTTPClient(cfg.FetchTimeout)
+
 	req, err := http.NewRequestWithContext(ctxWithTimeout, http.MethodGet, targetURL, nil)
 
 	if err != nil {
 		metrics.Requests.WithLabelValues("failed").Inc()
-		return nil, fmt.Errorf("failed to create request. : %w", err)
+		return nil, newError(ErrInvalidInput, err, "failed to create request")
 	}
 
-	req.Header.Set("User-Agent", "WebAnalyzer/1.0")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Encoding", utils.AcceptEncoding)
+	if fetch != nil {
+		if fetch.etag != "" {
+			req.Header.Set("If-None-Match", fetch.etag)
+		}
+		if fetch.lastModified != "" {
+			req.Header.Set("If-Modified-Since", fetch.lastModified)
+		}
+	}
 
 	resp, err := client.Do(req)
 
+	// A certificate that fails verification is what the TLS section reports on, so the
+	// page is fetched again without verification and the failure recorded.
+	var certErr error
+	if certErr = utils.CertificateError(err); certErr != nil && opts.Sections.Has(models.SectionTLS) {
+		resp, err = utils.NewUnverifiedHTTPClient(cfg.FetchTimeout).Do(req.Clone(ctxWithTimeout))
+	}
+
 	if err != nil {
 		metrics.Requests.WithLabelValues("failed").Inc()
-		return nil, fmt.Errorf("failed to fetch page. : %w", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if fetch != nil {
+		fetch.header = resp.Header
+		if resp.StatusCode == http.StatusNotModified && (fetch.etag != "" || fetch.lastModified != "") {
+			metrics.Requests.WithLabelValues("not_modified").Inc()
+			return nil, errNotModified
+		}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if resp.StatusCode != http.StatusOK && !(opts.AnyStatus && isHTML(contentType)) {
 		metrics.Requests.WithLabelValues("error").Inc()
-		return nil, fmt.Errorf("request failed: received status code %d (%s)",
-			resp.StatusCode, http.StatusText(resp.StatusCode))
+		return nil, &Error{
+			Kind:       ErrUpstreamStatus,
+			Message:    fmt.Sprintf("request failed: received status code %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode: resp.StatusCode,
+		}
+	}
 
+	contentEncoding := resp.Header.Get("Content-Encoding")
+	compressed := &utils.ByteCounter{Reader: resp.Body}
+	content, err := utils.DecodeContentEncoding(compressed, contentEncoding)
+	if err != nil {
+		metrics.Requests.WithLabelValues("failed").Inc()
+		return nil, bodyError(err)
+	}
+	limits := effectiveLimits(opts.Limits, cfg.Limits)
+	limited := &utils.LimitedReader{Reader: content, Limit: limits.MaxBodyBytes}
+	uncompressed := &utils.ByteCounter{Reader: limited}
+
+	body, encodingName, err := utils.DecodeCharset(uncompressed, contentType)
+	if err != nil {
+		metrics.Requests.WithLabelValues("failed").Inc()
+		return nil, bodyError(err)
 	}
 
-	doc, err := html.Parse(resp.Body)
+	doc, parseLimit, err := utils.ParseHTML(body, limits)
 
 	if err != nil {
 		metrics.Requests.WithLabelValues("parse_error").Inc()
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, bodyError(fmt.Errorf("failed to parse HTML: %w", err))
 	}
 
 	analysis := &models.PageAnalysis{
-		Headings:    make(map[string]int),
-		LinksStatus: make(map[string]string),
-		PageSize:    resp.ContentLength,
-		LoadTime:    time.Since(time.Now().Add(-10 * time.Second)).Milliseconds(), // Approximation of load Time
+		StatusCode:      resp.StatusCode,
+		Headers:         responseHeaders(resp.Header),
+		Headings:        make(map[string]int),
+		LinksStatus:     make(map[string]string),
+		PageSize:        uncompressed.N,
+		CompressedSize:  compressed.N,
+		ContentEncoding: contentEncodingName(contentEncoding),
+		Charset:         encodingName,
+		LoadTime:        time.Since(time.Now().Add(-10 * time.Second)).Milliseconds(), // Approximation of load Time
 	}
 
-	versionChan := make(chan string, 1)
-
-	go func() {
-		versionChan <- utils.DetectHTMLVersion(resp)
-	}()
-
-	linksChan := make(chan models.LinkInfo, 100)
-	resultChan := make(chan error, 1)
+	if opts.Sections.Has(models.SectionHTMLVersion) {
+		analysis.HTMLVersion = utils.DetectHTMLVersion(doc, contentType)
+		analysis.DocumentMode = utils.DetectDocumentMode(doc)
+	}
 
-	go func() {
-		utils.TraverseHTML(doc, analysis, targetURL, linksChan)
-		close(linksChan)
-	}()
+	if resp.StatusCode == http.StatusOK {
+		analysis.Soft404, analysis.Soft404Reason = utils.DetectSoft404(doc)
+	}
 
-	linkWg := &sync.WaitGroup{}
-	linksProcessed := 0
+	if limited.Exceeded {
+		analysis.Truncate(fmt.Sprintf("response body exceeded %d bytes", limits.MaxBodyBytes))
+	}
+	if parseLimit != "" {
+		analysis.Truncate(parseLimit)
+	}
 
-	maxWorkers := runtime.NumCPU() * 2 // Use CPU count = 6  determine concurrency level
+	metrics.ContentEncodings.WithLabelValues(analysis.ContentEncoding).Inc()
 
-	// Create a worker pool for checking links
-	for i := 0; i < maxWorkers; i++ {
-		linkWg.Add(1)
-		go func() {
-			defer linkWg.Done()
-			for link := range linksChan {
-				if ctx.Err() != nil {
-					return
-				}
-				utils.CheckLink(link, analysis)
-				linksProcessed++
-			}
-		}()
+	if resp.TLS != nil && opts.Sections.Has(models.SectionTLS) {
+		analysis.TLS = utils.InspectTLS(resp.TLS, resp.Request.URL.Hostname())
+		if certErr != nil {
+			analysis.TLS.VerificationError = certErr.Error()
+		}
 	}
 
+	// Links resolve, and mixed content is judged, against the page after redirects.
+	pageURL := resp.Request.URL.String()
+	linksChan := make(chan models.LinkInfo, cfg.LinkBuffer)
 	go func() {
-		linkWg.Wait()
-		close(resultChan)
+		utils.TraverseHTML(doc, analysis, pageURL, linksChan, utils.TraverseOptions{
+			Limits:        limits,
+			Sections:      opts.Sections,
+			SkipLinkCheck: opts.SkipLinkCheck,
+			LinkFilter:    filter,
+			Context:       ctxWithTimeout,
+		})
+		close(linksChan)
 	}()
 
-	select {
-	case <-resultChan: // successfully processed links
-	case <-ctx.Done():
-		return nil, fmt.Errorf("analysis cancelled / timed out: %w", ctx.Err())
+	// The traversal owns analysis until it closes linksChan, which happens before
+	// checkLinks returns; only then are the link results merged in.
+	tally := checkLinks(ctxWithTimeout, checker, workers, linksChan, onLink)
+	if err := ctx.Err(); errors.Is(err, context.Canceled) {
+		return nil, fmt.Errorf("analysis cancelled: %w", err)
+	}
+	tally.apply(analysis)
+	if ctxWithTimeout.Err() != nil {
+		// The analysis deadline or the caller's passed: return what was checked so far.
+		analysis.Cancelled = true
 	}
 
-	analysis.HTMLVersion = <-versionChan
+	if opts.FlagExpiredCerts {
+		analysis.ExpiredCertLinks = expiredCertLinks(analysis.LinksStatus)
+	}
 
-	metrics.Requests.WithLabelValues("success").Inc()
-	metrics.LinksProcessed.Add(float64(linksProcessed))
+	outcome := "success"
+	if analysis.Cancelled {
+		outcome = "partial"
+	}
+	metrics.Requests.WithLabelValues(outcome).Inc()
 	metrics.AnalysisTime.Observe(float64(analysis.LoadTime) / 1000.0)
 
 	return analysis, nil
@@ -127,62 +280,171 @@ func HandleAnalyze(c *gin.Context) {
 	startTime := time.Now()
 	logger := slog.With("handler", "analyze", "requestID", c.GetString("requestID"))
 
-	targetURL := c.Query("url")
-	if targetURL == "" {
+	request, fieldErrs, err := parseAnalyzeRequest(c)
+	if err != nil {
+		logger.Warn("invalid analysis request", "error", err)
+		status, resp := ResponseFor(err)
+		apierror.Write(c, status, resp)
+		return
+	}
+	if len(fieldErrs) == 0 && request.URL == "" {
 		logger.Warn("missing URL parameter")
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.CodeInvalidInput,
 			Error:   "URL parameter is required",
 			Details: "Please provide a valid URL to analyze",
 		})
 		return
 	}
+	targetURL := request.URL
+
+	opts, optErrs := optionsFromRequest(request, CurrentSettings())
+	fieldErrs = append(fieldErrs, optErrs...)
+	format, formatErrs := responseFormat(c)
+	fieldErrs = append(fieldErrs, formatErrs...)
+	if request.CallbackURL != "" {
+		fieldErrs = append(fieldErrs, validateCallbackURL(request.CallbackURL)...)
+		if err := validateURL(targetURL); err != nil {
+			fieldErrs = append(fieldErrs, models.FieldError{Field: "url", Message: "must be an absolute http or https URL"})
+		}
+	}
+	if len(fieldErrs) > 0 {
+		logger.Warn("invalid analysis options", "errors", fieldErrs)
+		status, resp := ResponseFor(&RequestError{Fields: fieldErrs})
+		apierror.Write(c, status, resp)
+		return
+	}
+
+	if request.CallbackURL != "" {
+		acceptCallback(c, logger, targetURL, request.CallbackURL, opts)
+		return
+	}
 
 	logger.Info("starting analysis..", "url", targetURL)
 
 	resultChan := make(chan struct {
-		result *models.PageAnalysis
-		err    error
+		analysis cachedAnalysis
+		err      error
 	}, 1)
 
+	cc := requestCacheControl(c)
 	go func() {
-		result, err := AnalyzePage(c.Request.Context(), targetURL)
+		analysis, err := runAnalysis(c.Request.Context(), targetURL, c.GetString("requestID"), opts, cc)
 		resultChan <- struct {
-			result *models.PageAnalysis
-			err    error
-		}{result, err}
+			analysis cachedAnalysis
+			err      error
+		}{analysis, err}
 	}()
 
 	select {
 	case res := <-resultChan:
 		if res.err != nil {
 			logger.Error("analysis failed..", "error", res.err)
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error:   "Analysis failed",
-				Details: res.err.Error(),
-			})
+			status, resp := ResponseFor(res.err)
+			apierror.Write(c, status, resp)
 			return
 		}
-		res.result.AnalysisDuration = time.Since(startTime).String()
+		result := res.analysis.result
+		if res.analysis.status != "" {
+			c.Header("X-Cache", res.analysis.status)
+		}
+		if res.analysis.status == cacheHit {
+			c.Header("Age", strconv.Itoa(int(res.analysis.age.Seconds())))
+			logger.Info("analysis served from cache", "resultID", result.ResultID, "age", res.analysis.age)
+		}
+		if result.ResultID != "" {
+			c.Header("X-Result-ID", result.ResultID)
+		}
 
-		logger.Info("analysis completed..", "duration", res.result.AnalysisDuration,
-			"htmlVersion", res.result.HTMLVersion, "internalLinks", res.result.InternalLinks,
-			"externalLinks", res.result.ExternalLinks)
+		if result.Truncated {
+			logger.Warn("analysis truncated", "reason", result.TruncatedReason)
+		}
 
-		c.JSON(http.StatusOK, res.result)
+		logger.Info("analysis completed..", "duration", time.Since(startTime).String(),
+			"htmlVersion", result.HTMLVersion, "documentMode", result.DocumentMode, "internalLinks", result.InternalLinks,
+			"externalLinks", result.ExternalLinks)
+
+		if format != export.JSON {
+			writeReport(c, format, export.Report{
+				URL:        targetURL,
+				ResultID:   result.ResultID,
+				AnalyzedAt: time.Now().Add(-res.analysis.age),
+				Analysis:   result,
+			})
+			return
+		}
+		c.JSON(http.StatusOK, result)
 
 	case <-c.Request.Context().Done():
 		logger.Error("request cancelled by client")
-		c.JSON(http.StatusRequestTimeout, models.ErrorResponse{
+		apierror.Write(c, http.StatusRequestTimeout, models.ErrorResponse{
+			Code:    models.CodeCancelled,
 			Error:   "Request cancelled",
 			Details: "The analysis request was cancelled / timed out",
 		})
 	}
 }
 
+// isHTML reports whether contentType is an HTML media type. A missing Content-Type is
+// taken to be HTML, as it is for 200 responses.
+func isHTML(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// responseHeaders flattens the page's response headers for PageAnalysis.Headers.
+// Set-Cookie is left out so that stored results do not keep session cookies.
+func responseHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if name != "Set-Cookie" {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+	return headers
+}
+
 func validateURL(targetURL string) error {
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
-		return fmt.Errorf("invalid URL format: %s", targetURL)
+		return newError(ErrInvalidInput, nil, "invalid URL format: %s", targetURL)
 	}
 	return nil
 }
+
+func expiredCertLinks(linksStatus map[string]string) []string {
+	var links []string
+	for link, status := range linksStatus {
+		if strings.HasPrefix(status, utils.ExpiredCertStatusPrefix) {
+			links = append(links, link)
+		}
+	}
+	sort.Strings(links)
+	return links
+}
+
+func contentEncodingName(contentEncoding string) string {
+	if contentEncoding == "" {
+		return "identity"
+	}
+	return strings.ToLower(strings.ReplaceAll(contentEncoding, " ", ""))
+}
+
+func effectiveLimits(limits, defaults models.ParseLimits) models.ParseLimits {
+	if limits.MaxBodyBytes == 0 {
+		limits.MaxBodyBytes = defaults.MaxBodyBytes
+	}
+	if limits.MaxNodes == 0 {
+		limits.MaxNodes = defaults.MaxNodes
+	}
+	if limits.MaxLinks == 0 {
+		limits.MaxLinks = defaults.MaxLinks
+	}
+	if limits.MaxDepth == 0 {
+		limits.MaxDepth = defaults.MaxDepth
+	}
+	return limits
+}
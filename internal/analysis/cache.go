@@ -0,0 +1,346 @@
+package analysis
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/pkg/metrics"
+)
+
+// X-Cache values.
+const (
+	cacheHit  = "HIT"
+	cacheMiss = "MISS"
+)
+
+// CacheConfig bounds the result cache.
+type CacheConfig struct {
+	MaxEntries int           // results kept, least recently used evicted first
+	DefaultTTL time.Duration // freshness of pages that send no Cache-Control max-age or Expires
+	MaxAge     time.Duration // a cached analysis is rerun after this long, even if the page revalidates
+}
+
+// ResultCache keeps recent analyses keyed by normalized URL and options. An entry is
+// fresh for as long as the analyzed page allows; a stale entry with an ETag or
+// Last-Modified is revalidated with a conditional fetch, and reused if the page
+// answers 304 Not Modified.
+type ResultCache struct {
+	cfg CacheConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key          string
+	result       *models.PageAnalysis // shared by every hit, never modified
+	etag         string
+	lastModified string
+	analyzedAt   time.Time
+	freshUntil   time.Time
+}
+
+func NewResultCache(cfg CacheConfig) *ResultCache {
+	return &ResultCache{
+		cfg:     cfg,
+		now:     time.Now,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+type cacheHolder struct{ cache *ResultCache }
+
+var resultCache atomic.Pointer[cacheHolder]
+
+// SetResultCache makes HandleAnalyze serve repeated requests from cache. Nil disables caching.
+func SetResultCache(cache *ResultCache) {
+	resultCache.Store(&cacheHolder{cache: cache})
+}
+
+func currentResultCache() *ResultCache {
+	if h := resultCache.Load(); h != nil {
+		return h.cache
+	}
+	return nil
+}
+
+// lookup returns the entry for key and whether it can be served without revalidation.
+// Entries past MaxAge are dropped.
+func (rc *ResultCache) lookup(key string) (*cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	now := rc.now()
+	if now.Sub(entry.analyzedAt) >= rc.cfg.MaxAge {
+		rc.lru.Remove(elem)
+		delete(rc.entries, key)
+		return nil, false
+	}
+	rc.lru.MoveToFront(elem)
+	return entry, now.Before(entry.freshUntil)
+}
+
+// put caches result unless the page's headers forbid storing it.
+func (rc *ResultCache) put(key string, result *models.PageAnalysis, header http.Header) {
+	ttl, ok := rc.freshness(header)
+	if !ok {
+		rc.remove(key)
+		return
+	}
+
+	now := rc.now()
+	entry := &cacheEntry{
+		key:          key,
+		result:       result,
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+		analyzedAt:   now,
+		freshUntil:   now.Add(ttl),
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if elem, ok := rc.entries[key]; ok {
+		elem.Value = entry
+		rc.lru.MoveToFront(elem)
+		return
+	}
+	rc.entries[key] = rc.lru.PushFront(entry)
+	for rc.lru.Len() > rc.cfg.MaxEntries {
+		oldest := rc.lru.Back()
+		rc.lru.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// revalidated extends a stale entry after the page answered 304 Not Modified.
+func (rc *ResultCache) revalidated(entry *cacheEntry, header http.Header) {
+	ttl, ok := rc.freshness(header)
+	if !ok {
+		rc.remove(entry.key)
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if elem, ok := rc.entries[entry.key]; ok && elem.Value == entry {
+		updated := *entry
+		updated.freshUntil = rc.now().Add(ttl)
+		if etag := header.Get("ETag"); etag != "" {
+			updated.etag = etag
+		}
+		elem.Value = &updated
+	}
+}
+
+func (rc *ResultCache) remove(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if elem, ok := rc.entries[key]; ok {
+		rc.lru.Remove(elem)
+		delete(rc.entries, key)
+	}
+}
+
+// freshness returns how long a page with these response headers may be served from cache,
+// or false when it must not be cached. no-cache makes entries stale immediately, so every
+// request revalidates them.
+func (rc *ResultCache) freshness(header http.Header) (time.Duration, bool) {
+	directives := parseCacheControl(header.Values("Cache-Control"))
+	if _, ok := directives["no-store"]; ok {
+		return 0, false
+	}
+	if _, ok := directives["no-cache"]; ok {
+		return 0, true
+	}
+
+	ttl := rc.cfg.DefaultTTL
+	if age, ok := directives["s-maxage"]; ok {
+		ttl = parseSeconds(age)
+	} else if age, ok := directives["max-age"]; ok {
+		ttl = parseSeconds(age)
+	} else if expires := header.Get("Expires"); expires != "" {
+		ttl = 0
+		if at, err := http.ParseTime(expires); err == nil {
+			date, err := http.ParseTime(header.Get("Date"))
+			if err != nil {
+				date = rc.now()
+			}
+			ttl = max(at.Sub(date), 0)
+		}
+	}
+	return min(ttl, rc.cfg.MaxAge), true
+}
+
+func parseCacheControl(values []string) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if name != "" {
+				directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+			}
+		}
+	}
+	return directives
+}
+
+func parseSeconds(s string) time.Duration {
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// cacheControl is what the caller's Cache-Control header asks of the result cache.
+type cacheControl struct {
+	noCache bool // run a fresh analysis
+	noStore bool // run a fresh analysis and do not cache it
+}
+
+func requestCacheControl(c *gin.Context) cacheControl {
+	directives := parseCacheControl(c.Request.Header.Values("Cache-Control"))
+	_, noCache := directives["no-cache"]
+	_, noStore := directives["no-store"]
+	return cacheControl{
+		noCache: noCache || noStore || c.GetHeader("Pragma") == "no-cache",
+		noStore: noStore,
+	}
+}
+
+// cachedAnalysis is the outcome of runAnalysis.
+type cachedAnalysis struct {
+	result *models.PageAnalysis
+	status string        // X-Cache value, empty when caching is disabled
+	age    time.Duration // time since the analysis ran, for hits
+}
+
+// runAnalysis analyzes targetURL, or serves it from the result cache, and stores fresh
+// results. Concurrent identical requests share one analysis. Results may be shared
+// between callers and must not be modified.
+func runAnalysis(ctx context.Context, targetURL, requestID string, opts models.AnalysisOptions, cc cacheControl) (cachedAnalysis, error) {
+	cache := currentResultCache()
+	key := cacheKey(targetURL, opts)
+
+	if cache != nil && !cc.noCache {
+		if entry, fresh := cache.lookup(key); fresh {
+			metrics.CacheLookups.WithLabelValues("hit").Inc()
+			return cachedAnalysis{result: entry.result, status: cacheHit, age: cache.now().Sub(entry.analyzedAt)}, nil
+		}
+	}
+
+	// Callers bypassing the cache only share analyses with callers that bypass it too.
+	flightKey := key
+	if cc.noCache {
+		flightKey += ":no-cache"
+	}
+	if cc.noStore {
+		flightKey += ":no-store"
+	}
+	return analyses.do(ctx, flightKey, func(ctx context.Context) (cachedAnalysis, error) {
+		return analyzeAndCache(ctx, cache, key, targetURL, requestID, opts, cc)
+	})
+}
+
+// analyzeAndCache runs one analysis, revalidating a stale cache entry when there is one.
+func analyzeAndCache(ctx context.Context, cache *ResultCache, key, targetURL, requestID string,
+	opts models.AnalysisOptions, cc cacheControl) (cachedAnalysis, error) {
+
+	startTime := time.Now()
+
+	var fetch *pageFetch
+	var entry *cacheEntry
+	if cache != nil {
+		fetch = &pageFetch{}
+		if cc.noCache {
+			metrics.CacheLookups.WithLabelValues("bypass").Inc()
+		} else if entry, _ = cache.lookup(key); entry != nil {
+			fetch.etag, fetch.lastModified = entry.etag, entry.lastModified
+		}
+	}
+
+	result, err := analyzePage(ctx, targetURL, opts, fetch, nil)
+	if errors.Is(err, errNotModified) {
+		metrics.CacheLookups.WithLabelValues("revalidated").Inc()
+		cache.revalidated(entry, fetch.header)
+		return cachedAnalysis{result: entry.result, status: cacheHit, age: cache.now().Sub(entry.analyzedAt)}, nil
+	}
+	if err != nil {
+		return cachedAnalysis{}, err
+	}
+
+	result.AnalysisDuration = time.Since(startTime).String()
+	saveResult(context.WithoutCancel(ctx), targetURL, requestID, result)
+
+	if cache == nil {
+		return cachedAnalysis{result: result}, nil
+	}
+	if !cc.noCache {
+		metrics.CacheLookups.WithLabelValues("miss").Inc()
+	}
+	if cc.noStore || result.Cancelled { // partial results are not worth serving again
+		cache.remove(key)
+	} else {
+		cache.put(key, result, fetch.header)
+	}
+	return cachedAnalysis{result: result, status: cacheMiss}, nil
+}
+
+// cacheKey identifies an analysis by its normalized URL and options.
+func cacheKey(targetURL string, opts models.AnalysisOptions) string {
+	opts.Sections = slices.Clone(opts.Sections)
+	slices.Sort(opts.Sections)
+	encodedOpts, _ := json.Marshal(opts)
+
+	sum := sha256.Sum256(append([]byte(normalizeURL(targetURL)+"\n"), encodedOpts...))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeURL lowercases the scheme and host, drops default ports and the fragment,
+// and sorts the query parameters.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if strings.Contains(host, ":") { // IPv6 literal
+		host = "[" + host + "]"
+	}
+	if port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
@@ -0,0 +1,150 @@
+package analysis
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/utils"
+	"web-analyzer/internal/webhook"
+)
+
+// Webhook events sent to callback URLs.
+const (
+	EventAnalysisCompleted = "analysis.completed"
+	EventAnalysisFailed    = "analysis.failed"
+)
+
+// maxPendingCallbacks bounds the asynchronous analyses running at once.
+const maxPendingCallbacks = 100
+
+type callbackHolder struct {
+	sender  *webhook.Sender
+	pending chan struct{}
+}
+
+var callbacks atomic.Pointer[callbackHolder]
+
+// running tracks the callback analyses in flight, across SetCallbackSender calls.
+var running sync.WaitGroup
+
+// SetCallbackSender enables the callback_url option: such requests are answered with 202
+// and their result is delivered with sender. A nil sender disables the option.
+func SetCallbackSender(sender *webhook.Sender) {
+	if sender == nil {
+		callbacks.Store(nil)
+		return
+	}
+	callbacks.Store(&callbackHolder{sender: sender, pending: make(chan struct{}, maxPendingCallbacks)})
+}
+
+// validateCallbackURL checks a callback destination. Addresses the network policy blocks
+// are rejected up front when given literally; host names are checked again when dialing.
+func validateCallbackURL(raw string) []models.FieldError {
+	reject := func(message string) []models.FieldError {
+		return []models.FieldError{{Field: "callback_url", Message: message}}
+	}
+
+	if callbacks.Load() == nil {
+		return reject("callbacks are not enabled on this server")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return reject("must be an absolute http or https URL")
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil {
+		if err := utils.NetworkGuard().Check(addr); err != nil {
+			return reject("destination not allowed: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// acceptCallback answers 202 and runs the analysis in the background, POSTing the result
+// or the error to callbackURL.
+func acceptCallback(c *gin.Context, logger *slog.Logger, targetURL, callbackURL string, opts models.AnalysisOptions) {
+	holder := callbacks.Load()
+	select {
+	case holder.pending <- struct{}{}:
+	default:
+		logger.Warn("too many pending callbacks")
+		c.Header("Retry-After", "10")
+		apierror.Write(c, http.StatusServiceUnavailable, models.ErrorResponse{
+			Code:    models.CodeUnavailable,
+			Error:   "Too many pending analyses",
+			Details: "Retry later or analyze synchronously without callback_url",
+		})
+		return
+	}
+
+	requestID := c.GetString("requestID")
+	cc := requestCacheControl(c)
+	// The key ID is set by the server's auth middleware; deliveries are only listed to it.
+	delivery := holder.sender.NewDelivery(callbackURL, c.GetString("apiKeyID"))
+	logger = logger.With("deliveryID", delivery.ID, "callbackURL", callbackURL)
+	logger.Info("analysis accepted for callback", "url", targetURL)
+
+	// The analysis outlives the request but keeps its values, such as the request id.
+	ctx := context.WithoutCancel(c.Request.Context())
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		defer func() { <-holder.pending }()
+		runCallback(ctx, logger, holder.sender, delivery, requestID, targetURL, opts, cc)
+	}()
+
+	c.JSON(http.StatusAccepted, models.CallbackAccepted{
+		RequestID:   requestID,
+		DeliveryID:  delivery.ID,
+		CallbackURL: callbackURL,
+	})
+}
+
+// DrainCallbacks waits for the callback analyses in flight to deliver their results, or
+// until ctx is done. It is called on shutdown, once no new requests are accepted.
+func DrainCallbacks(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func runCallback(ctx context.Context, logger *slog.Logger, sender *webhook.Sender, delivery *webhook.Delivery,
+	requestID, targetURL string, opts models.AnalysisOptions, cc cacheControl) {
+
+	payload := &models.CallbackPayload{RequestID: requestID, URL: targetURL}
+
+	analysis, err := runAnalysis(ctx, targetURL, requestID, opts, cc)
+	if err != nil {
+		logger.Error("analysis failed..", "error", err)
+		_, errResp := ResponseFor(err)
+		payload.Event = EventAnalysisFailed
+		payload.Error = &errResp
+	} else {
+		payload.Event = EventAnalysisCompleted
+		payload.ResultID = analysis.result.ResultID
+		payload.Analysis = analysis.result
+	}
+
+	if err := sender.Deliver(ctx, delivery, payload.Event, payload); err != nil {
+		logger.Error("callback delivery failed", "error", err)
+		return
+	}
+	logger.Info("callback delivered", "event", payload.Event, "attempts", len(delivery.Attempts))
+}
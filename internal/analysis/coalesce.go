@@ -0,0 +1,110 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"web-analyzer/pkg/metrics"
+)
+
+// flightGroup coalesces concurrent identical analyses into one. The shared analysis runs
+// on a context detached from its callers' cancellation, bounded by the first caller's
+// deadline, and is cancelled only once every caller waiting for it has given up.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done     chan struct{}
+	result   cachedAnalysis
+	err      error
+	deadline time.Time // the first caller's deadline, zero when it had none
+	waiters  int       // callers still waiting, guarded by flightGroup.mu
+	cancel   context.CancelFunc
+}
+
+var analyses = &flightGroup{flights: make(map[string]*flight)}
+
+// do runs fn once for all concurrent callers with the same key and returns its result.
+// A caller only joins a flight that ends no later than its own deadline, so that it
+// gets the partial result of a flight cut short by that deadline; other callers run fn
+// on their own. A caller whose ctx is cancelled stops waiting and gets ctx.Err(); the
+// others are unaffected.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (cachedAnalysis, error)) (cachedAnalysis, error) {
+	deadline, _ := ctx.Deadline()
+
+	g.mu.Lock()
+	if f, ok := g.flights[key]; ok {
+		if !f.servesDeadline(deadline) {
+			g.mu.Unlock()
+			return fn(ctx)
+		}
+		f.waiters++
+		g.mu.Unlock()
+		metrics.CoalescedRequests.Inc()
+		return g.wait(ctx, key, f)
+	}
+
+	// The analysis keeps the first caller's context values and deadline but not its
+	// cancellation.
+	flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	if !deadline.IsZero() {
+		flightCtx, cancel = context.WithDeadline(flightCtx, deadline)
+	}
+	f := &flight{done: make(chan struct{}), deadline: deadline, waiters: 1, cancel: cancel}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		f.result, f.err = fn(flightCtx)
+
+		g.mu.Lock()
+		g.forget(key, f)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+
+	return g.wait(ctx, key, f)
+}
+
+// servesDeadline reports whether f ends no later than deadline, zero meaning none.
+func (f *flight) servesDeadline(deadline time.Time) bool {
+	if deadline.IsZero() || f.deadline.IsZero() {
+		return deadline.IsZero() && f.deadline.IsZero()
+	}
+	return !f.deadline.After(deadline)
+}
+
+func (g *flightGroup) wait(ctx context.Context, key string, f *flight) (cachedAnalysis, error) {
+	select {
+	case <-f.done:
+		return f.result, f.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// The flight's deadline has passed too: it is returning what it checked so far.
+			<-f.done
+			return f.result, f.err
+		}
+
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody wants the result any more; later callers start a new analysis.
+			f.cancel()
+			g.forget(key, f)
+		}
+		g.mu.Unlock()
+		return cachedAnalysis{}, ctx.Err()
+	}
+}
+
+// forget removes f unless a newer flight already took its key. g.mu must be held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
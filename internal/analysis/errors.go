@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/netguard"
+)
+
+// Kinds of analysis failure. Errors returned by the analysis functions wrap one of them,
+// or context.Canceled when the caller went away; test with errors.Is.
+var (
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrUpstreamUnreachable = errors.New("page unreachable")
+	ErrUpstreamStatus      = errors.New("page answered with an error status")
+	ErrUpstreamInvalid     = errors.New("page could not be decoded")
+	ErrTimeout             = errors.New("analysis timed out")
+	ErrTooLarge            = errors.New("request too large")
+	ErrBlocked             = netguard.ErrBlocked
+)
+
+// Error is an analysis failure of one of the kinds above.
+type Error struct {
+	Kind       error
+	Message    string // what failed
+	StatusCode int    // the page's status code, for ErrUpstreamStatus
+	Err        error  // the cause, if any
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+func newError(kind error, cause error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// requestError classifies a failed page request. Cancellation by the caller is kept as
+// it is, so that it is not mistaken for a timeout.
+func requestError(err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, netguard.ErrBlocked):
+		return newError(ErrBlocked, err, "destination not allowed")
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("analysis cancelled: %w", err)
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return newError(ErrTimeout, err, "page request timed out")
+	default:
+		return newError(ErrUpstreamUnreachable, err, "failed to fetch page")
+	}
+}
+
+// bodyError classifies a failure to read, decode or parse the page body.
+func bodyError(err error) error {
+	if classified := requestError(err); !errors.Is(classified, ErrUpstreamUnreachable) {
+		return classified // blocked, cancelled or timed out while reading
+	}
+	return newError(ErrUpstreamInvalid, err, "failed to read page body")
+}
+
+// ResponseFor maps an error returned by the analysis functions to its HTTP status and
+// error response.
+func ResponseFor(err error) (int, models.ErrorResponse) {
+	resp := models.ErrorResponse{Details: err.Error()}
+	var reqErr *RequestError
+	var status int
+	switch {
+	case errors.As(err, &reqErr):
+		status, resp.Code, resp.Error = http.StatusBadRequest, models.CodeInvalidInput, "Invalid analysis options"
+		resp.Details, resp.Fields = "One or more request fields are invalid", reqErr.Fields
+	case errors.Is(err, ErrInvalidInput):
+		status, resp.Code, resp.Error = http.StatusBadRequest, models.CodeInvalidInput, "Invalid analysis request"
+	case errors.Is(err, ErrBlocked):
+		status, resp.Code, resp.Error = http.StatusForbidden, models.CodeBlocked, "Destination not allowed"
+	case errors.Is(err, ErrTooLarge):
+		status, resp.Code, resp.Error = http.StatusRequestEntityTooLarge, models.CodeTooLarge, "Request too large"
+	case errors.Is(err, ErrUpstreamUnreachable):
+		// The URL is well formed but leads nowhere: a problem with the request's content
+		// rather than with this server or the page.
+		status, resp.Code, resp.Error = http.StatusUnprocessableEntity, models.CodeUpstreamUnreachable, "Page unreachable"
+	case errors.Is(err, ErrUpstreamStatus):
+		status, resp.Code, resp.Error = http.StatusBadGateway, models.CodeUpstreamStatus, "Page returned an error status"
+	case errors.Is(err, ErrUpstreamInvalid):
+		status, resp.Code, resp.Error = http.StatusBadGateway, models.CodeUpstreamInvalid, "Page could not be processed"
+	case errors.Is(err, ErrTimeout):
+		status, resp.Code, resp.Error = http.StatusGatewayTimeout, models.CodeTimeout, "Analysis timed out"
+	case errors.Is(err, context.Canceled):
+		status, resp.Code, resp.Error = http.StatusRequestTimeout, models.CodeCancelled, "Request cancelled"
+	default:
+		status, resp.Code, resp.Error = http.StatusInternalServerError, models.CodeInternal, "Analysis failed"
+	}
+	return status, resp
+}
@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/export"
+	"web-analyzer/internal/models"
+)
+
+// responseFormat picks the format of an analysis response: the format query parameter
+// when given, otherwise the best match for the Accept header, JSON by default.
+func responseFormat(c *gin.Context) (export.Format, []models.FieldError) {
+	if raw, ok := c.GetQuery("format"); ok {
+		format, err := export.ParseFormat(raw)
+		if err != nil {
+			return "", []models.FieldError{{Field: "format", Message: "must be one of " + export.FormatNames()}}
+		}
+		return format, nil
+	}
+
+	c.Writer.Header().Add("Vary", "Accept")
+	offered := make([]string, len(export.Formats))
+	for i, format := range export.Formats {
+		offered[i] = format.MediaType()
+	}
+	negotiated := c.NegotiateFormat(offered...)
+	for _, format := range export.Formats {
+		if format.MediaType() == negotiated {
+			return format, nil
+		}
+	}
+	return export.JSON, nil
+}
+
+// writeReport answers 200 with report in format, which must not be JSON: JSON responses
+// keep their endpoint's own shape.
+func writeReport(c *gin.Context, format export.Format, report export.Report) {
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, report); err != nil {
+		slog.Error("failed to export analysis", "format", format, "url", report.URL, "error", err)
+		apierror.Write(c, http.StatusInternalServerError, models.ErrorResponse{Code: models.CodeInternal, Error: "Failed to export result"})
+		return
+	}
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
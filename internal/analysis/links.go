@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"context"
+	"sync"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/utils"
+	"web-analyzer/pkg/metrics"
+)
+
+// linkTally aggregates the link checks of one analysis.
+type linkTally struct {
+	status    map[string]string
+	broken    int
+	blocked   int
+	processed int
+	unchecked int // links dropped once ctx was done
+}
+
+type linkCheck struct {
+	result  utils.LinkResult
+	checked bool
+}
+
+// checkLinks checks the links received on links with the given number of workers until
+// links is closed. The workers only send their results; checkLinks alone aggregates them
+// and calls onLink, so neither needs locking. Once ctx is done the workers keep draining
+// links without checking them, so that the sender never blocks and every link is counted.
+func checkLinks(ctx context.Context, checker utils.LinkChecker, workers int, links <-chan models.LinkInfo,
+	onLink func(LinkResult)) linkTally {
+
+	results := make(chan linkCheck, workers)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range links {
+				result, checked := checker.Result(ctx, link)
+				results <- linkCheck{result: result, checked: checked}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	tally := linkTally{status: make(map[string]string)}
+	for check := range results {
+		if !check.checked {
+			if ctx.Err() != nil {
+				tally.unchecked++
+			}
+			continue
+		}
+		r := check.result
+		tally.processed++
+		tally.status[r.Link.URL] = r.Status
+		switch {
+		case r.Blocked:
+			tally.blocked++
+		case r.Broken:
+			tally.broken++
+		}
+		if onLink != nil {
+			onLink(LinkResult{URL: r.Link.URL, External: r.Link.IsExternal, Status: r.Status})
+		}
+	}
+
+	metrics.LinksProcessed.Add(float64(tally.processed))
+	metrics.BrokenLinks.Add(float64(tally.broken))
+	return tally
+}
+
+// apply merges the tally into analysis.
+func (t linkTally) apply(analysis *models.PageAnalysis) {
+	for link, status := range t.status {
+		analysis.LinksStatus[link] = status
+	}
+	analysis.BrokenLinks += t.broken
+	analysis.BlockedLinks += t.blocked
+	analysis.SkippedLinks += t.unchecked
+}
@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/http/httpguts"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/utils"
+)
+
+// MaxRequestBodyBytes bounds the JSON body of an analyze request.
+const MaxRequestBodyBytes = 64 << 10
+
+// Batch limits shared by the REST and gRPC batch endpoints.
+const (
+	// MaxBatchSize is the most requests one batch may hold.
+	MaxBatchSize = 20
+	// BatchConcurrency is how many requests of a batch are analyzed at once.
+	BatchConcurrency = 4
+)
+
+const (
+	maxLinkPatterns    = 20
+	maxUserAgentLength = 256
+	minLinkTimeout     = 100 * time.Millisecond
+)
+
+// parseAnalyzeRequest reads the request options from the JSON body of a POST or the
+// query string of a GET. The error is set for bodies over the size limit.
+func parseAnalyzeRequest(c *gin.Context) (models.AnalyzeRequest, []models.FieldError, error) {
+	var req models.AnalyzeRequest
+
+	if c.Request.Method == http.MethodPost {
+		dec := json.NewDecoder(http.MaxBytesReader(c.Writer, c.Request.Body, MaxRequestBodyBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return req, nil, newError(ErrTooLarge, nil, "request body exceeds %d bytes", tooLarge.Limit)
+			}
+			return req, []models.FieldError{{Field: "body", Message: "invalid JSON: " + err.Error()}}, nil
+		}
+		return req, nil, nil
+	}
+
+	var errs []models.FieldError
+	parseBool := func(field string) (bool, bool) {
+		raw, ok := c.GetQuery(field)
+		if !ok {
+			return false, false
+		}
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, models.FieldError{Field: field, Message: "must be true or false"})
+			return false, false
+		}
+		return v, true
+	}
+	parseInt := func(field string) int {
+		raw, ok := c.GetQuery(field)
+		if !ok {
+			return 0
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			errs = append(errs, models.FieldError{Field: field, Message: "must be an integer"})
+		}
+		return v
+	}
+
+	req.URL = c.Query("url")
+	req.FlagExpiredCerts, _ = parseBool("flag_expired_certs")
+	if checkLinks, ok := parseBool("check_links"); ok {
+		req.CheckLinks = &checkLinks
+	}
+	for _, value := range c.QueryArray("sections") {
+		req.Sections = append(req.Sections, strings.Split(value, ",")...)
+	}
+	req.LinkTimeout = c.Query("link_timeout")
+	req.LinkConcurrency = parseInt("link_concurrency")
+	req.MaxLinks = parseInt("max_links")
+	req.Include = c.QueryArray("include")
+	req.Exclude = c.QueryArray("exclude")
+	req.UserAgent = c.Query("user_agent")
+	req.AnyStatus, _ = parseBool("any_status")
+	req.CallbackURL = c.Query("callback_url")
+
+	return req, errs, nil
+}
+
+// optionsFromRequest validates req against the server settings. Callers may lower the
+// configured link concurrency and link limit but not raise them.
+func optionsFromRequest(req models.AnalyzeRequest, cfg Settings) (models.AnalysisOptions, []models.FieldError) {
+	var errs []models.FieldError
+	reject := func(field, format string, args ...any) {
+		errs = append(errs, models.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	opts := models.AnalysisOptions{
+		FlagExpiredCerts: req.FlagExpiredCerts,
+		SkipLinkCheck:    req.CheckLinks != nil && !*req.CheckLinks,
+		IncludeLinks:     req.Include,
+		ExcludeLinks:     req.Exclude,
+		UserAgent:        req.UserAgent,
+		AnyStatus:        req.AnyStatus,
+	}
+
+	for _, section := range req.Sections {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			continue
+		}
+		if !models.AllSections.Has(section) {
+			reject("sections", "unknown section %q, expected one of %s", section, strings.Join(models.AllSections, ", "))
+			continue
+		}
+		opts.Sections = append(opts.Sections, section)
+	}
+
+	if req.LinkTimeout != "" {
+		timeout, err := time.ParseDuration(req.LinkTimeout)
+		switch {
+		case err != nil:
+			reject("link_timeout", "must be a duration such as \"2s\"")
+		case timeout < minLinkTimeout || timeout > cfg.Timeout:
+			reject("link_timeout", "must be between %s and %s", minLinkTimeout, cfg.Timeout)
+		default:
+			opts.LinkTimeout = timeout
+		}
+	}
+
+	if req.LinkConcurrency != 0 {
+		if req.LinkConcurrency < 1 || req.LinkConcurrency > cfg.Workers {
+			reject("link_concurrency", "must be between 1 and %d", cfg.Workers)
+		}
+		opts.LinkConcurrency = req.LinkConcurrency
+	}
+
+	if req.MaxLinks != 0 {
+		maxLinks := cfg.Limits.MaxLinks
+		switch {
+		case req.MaxLinks < 1:
+			reject("max_links", "must be positive")
+		case maxLinks > 0 && req.MaxLinks > maxLinks:
+			reject("max_links", "must not exceed %d", maxLinks)
+		}
+		opts.Limits.MaxLinks = req.MaxLinks
+	}
+
+	if len(req.Include) > maxLinkPatterns {
+		reject("include", "at most %d patterns are allowed", maxLinkPatterns)
+	}
+	if len(req.Exclude) > maxLinkPatterns {
+		reject("exclude", "at most %d patterns are allowed", maxLinkPatterns)
+	}
+	if _, err := utils.NewLinkFilter(req.Include, nil); err != nil {
+		reject("include", "%v", err)
+	}
+	if _, err := utils.NewLinkFilter(nil, req.Exclude); err != nil {
+		reject("exclude", "%v", err)
+	}
+
+	if req.UserAgent != "" {
+		if len(req.UserAgent) > maxUserAgentLength || !httpguts.ValidHeaderFieldValue(req.UserAgent) {
+			reject("user_agent", "must be a valid header value of at most %d characters", maxUserAgentLength)
+		}
+	}
+
+	return opts, errs
+}
@@ -0,0 +1,242 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/diff"
+	"web-analyzer/internal/export"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/storage"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+type storeHolder struct{ store storage.Store }
+
+var resultStore atomic.Pointer[storeHolder]
+
+// SetResultStore makes HandleAnalyze save every successful analysis to store and enables
+// the result and history endpoints. A nil store disables both.
+func SetResultStore(store storage.Store) {
+	resultStore.Store(&storeHolder{store: store})
+}
+
+func currentResultStore() storage.Store {
+	if h := resultStore.Load(); h != nil {
+		return h.store
+	}
+	return nil
+}
+
+// saveResult stores result and sets its ResultID. Failures are logged but do not fail the analysis.
+func saveResult(ctx context.Context, targetURL, requestID string, result *models.PageAnalysis) {
+	store := currentResultStore()
+	if store == nil {
+		return
+	}
+
+	record := &models.AnalysisRecord{
+		ID:        uuid.New().String(),
+		URL:       targetURL,
+		RequestID: requestID,
+		CreatedAt: time.Now().UTC(),
+		Analysis:  result,
+	}
+	result.ResultID = record.ID
+
+	if err := store.Save(ctx, record); err != nil {
+		result.ResultID = ""
+		slog.Error("failed to store analysis result", "url", targetURL, "requestID", requestID, "error", err)
+	}
+}
+
+// HandleGetResult serves GET /api/v1/results/:id, as JSON or an export format.
+func HandleGetResult(c *gin.Context) {
+	store := currentResultStore()
+	if store == nil {
+		storageDisabled(c)
+		return
+	}
+
+	format, fieldErrs := responseFormat(c)
+	if len(fieldErrs) > 0 {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.CodeInvalidInput,
+			Error:   "Invalid result query",
+			Details: "One or more request fields are invalid",
+			Fields:  fieldErrs,
+		})
+		return
+	}
+
+	record, err := store.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, storage.ErrNotFound) {
+		apierror.Write(c, http.StatusNotFound, models.ErrorResponse{
+			Code:    models.CodeNotFound,
+			Error:   "Result not found",
+			Details: "No stored result has this id, or it has expired",
+		})
+		return
+	}
+	if err != nil {
+		slog.Error("failed to load analysis result", "id", c.Param("id"), "error", err)
+		apierror.Write(c, http.StatusInternalServerError, models.ErrorResponse{Code: models.CodeInternal, Error: "Failed to load result"})
+		return
+	}
+
+	if format != export.JSON {
+		writeReport(c, format, export.Report{
+			URL:        record.URL,
+			ResultID:   record.ID,
+			AnalyzedAt: record.CreatedAt,
+			Analysis:   record.Analysis,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// HandleHistory serves GET /api/v1/history?url=...&limit=...&offset=...
+func HandleHistory(c *gin.Context) {
+	store := currentResultStore()
+	if store == nil {
+		storageDisabled(c)
+		return
+	}
+
+	var fieldErrs []models.FieldError
+	targetURL := c.Query("url")
+	if targetURL == "" {
+		fieldErrs = append(fieldErrs, models.FieldError{Field: "url", Message: "is required"})
+	}
+	page := storage.Page{Limit: defaultHistoryLimit}
+	if raw, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxHistoryLimit {
+			fieldErrs = append(fieldErrs, models.FieldError{Field: "limit", Message: "must be between 1 and " + strconv.Itoa(maxHistoryLimit)})
+		}
+		page.Limit = limit
+	}
+	if raw, ok := c.GetQuery("offset"); ok {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			fieldErrs = append(fieldErrs, models.FieldError{Field: "offset", Message: "must be a non-negative integer"})
+		}
+		page.Offset = offset
+	}
+	if len(fieldErrs) > 0 {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.CodeInvalidInput,
+			Error:   "Invalid history query",
+			Details: "One or more request fields are invalid",
+			Fields:  fieldErrs,
+		})
+		return
+	}
+
+	records, total, err := store.History(c.Request.Context(), targetURL, page)
+	if err != nil {
+		slog.Error("failed to load analysis history", "url", targetURL, "error", err)
+		apierror.Write(c, http.StatusInternalServerError, models.ErrorResponse{Code: models.CodeInternal, Error: "Failed to load history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.HistoryResponse{
+		URL:     targetURL,
+		Total:   total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		Results: records,
+	})
+}
+
+// HandleDiff serves GET /api/v1/diff?from=<id>&to=<id>[&format=text], comparing two
+// stored results of the same URL.
+func HandleDiff(c *gin.Context) {
+	store := currentResultStore()
+	if store == nil {
+		storageDisabled(c)
+		return
+	}
+
+	var fieldErrs []models.FieldError
+	for _, field := range []string{"from", "to"} {
+		if c.Query(field) == "" {
+			fieldErrs = append(fieldErrs, models.FieldError{Field: field, Message: "is required"})
+		}
+	}
+	format := c.DefaultQuery("format", "json")
+	if _, ok := c.GetQuery("format"); !ok && c.NegotiateFormat(gin.MIMEJSON, gin.MIMEPlain) == gin.MIMEPlain {
+		format = "text"
+	}
+	if format != "json" && format != "text" {
+		fieldErrs = append(fieldErrs, models.FieldError{Field: "format", Message: "must be json or text"})
+	}
+	if len(fieldErrs) > 0 {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.CodeInvalidInput,
+			Error:   "Invalid diff query",
+			Details: "One or more request fields are invalid",
+			Fields:  fieldErrs,
+		})
+		return
+	}
+
+	records := make([]*models.AnalysisRecord, 2)
+	for i, field := range []string{"from", "to"} {
+		record, err := store.Get(c.Request.Context(), c.Query(field))
+		if errors.Is(err, storage.ErrNotFound) {
+			apierror.Write(c, http.StatusNotFound, models.ErrorResponse{
+				Code:    models.CodeNotFound,
+				Error:   "Result not found",
+				Details: fmt.Sprintf("No stored result has the %s id %q, or it has expired", field, c.Query(field)),
+			})
+			return
+		}
+		if err != nil {
+			slog.Error("failed to load analysis result", "id", c.Query(field), "error", err)
+			apierror.Write(c, http.StatusInternalServerError, models.ErrorResponse{Code: models.CodeInternal, Error: "Failed to load result"})
+			return
+		}
+		records[i] = record
+	}
+
+	if records[0].URL != records[1].URL {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.CodeInvalidInput,
+			Error:   "Results are not comparable",
+			Details: fmt.Sprintf("%q analyzed %s but %q analyzed %s", records[0].ID, records[0].URL, records[1].ID, records[1].URL),
+			Fields:  []models.FieldError{{Field: "to", Message: "must be a result for the same URL as from"}},
+		})
+		return
+	}
+
+	report := diff.Compare(records[0], records[1])
+	if format == "text" {
+		c.String(http.StatusOK, report.Text())
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func storageDisabled(c *gin.Context) {
+	apierror.Write(c, http.StatusNotImplemented, models.ErrorResponse{
+		Code:    models.CodeNotImplemented,
+		Error:   "Result storage is disabled",
+		Details: "The server was started without a result store",
+	})
+}
@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"web-analyzer/internal/models"
+)
+
+// RequestError is returned by Analyze and AnalyzeStream for requests that fail validation.
+type RequestError struct {
+	Fields []models.FieldError
+}
+
+func (e *RequestError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Message
+	}
+	return "invalid analysis request: " + strings.Join(msgs, "; ")
+}
+
+func (e *RequestError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// LinkResult is the outcome of one link check, reported by AnalyzeStream.
+type LinkResult struct {
+	URL      string
+	External bool
+	Status   string // as recorded in PageAnalysis.LinksStatus
+}
+
+// Analyze runs req the way POST /api/v1/analyze does, for callers outside gin: it
+// validates the request against the current settings, serves it from the result cache
+// when one is configured and stores the result. The result must not be modified.
+func Analyze(ctx context.Context, req models.AnalyzeRequest, requestID string) (*models.PageAnalysis, error) {
+	opts, err := requestOptions(req)
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := runAnalysis(ctx, req.URL, requestID, opts, cacheControl{})
+	if err != nil {
+		return nil, err
+	}
+	return analysis.result, nil
+}
+
+// AnalyzeStream is Analyze without the result cache: it always fetches the page and
+// calls onLink as each link check completes, one call at a time.
+func AnalyzeStream(ctx context.Context, req models.AnalyzeRequest, requestID string, onLink func(LinkResult)) (*models.PageAnalysis, error) {
+	opts, err := requestOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	result, err := analyzePage(ctx, req.URL, opts, nil, onLink)
+	if err != nil {
+		return nil, err
+	}
+	result.AnalysisDuration = time.Since(startTime).String()
+	saveResult(context.WithoutCancel(ctx), req.URL, requestID, result)
+	return result, nil
+}
+
+func requestOptions(req models.AnalyzeRequest) (models.AnalysisOptions, error) {
+	var fieldErrs []models.FieldError
+	if req.URL == "" {
+		fieldErrs = append(fieldErrs, models.FieldError{Field: "url", Message: "is required"})
+	}
+	if req.CallbackURL != "" {
+		fieldErrs = append(fieldErrs, models.FieldError{Field: "callback_url", Message: "is only supported by single requests to the REST API"})
+	}
+	opts, optErrs := optionsFromRequest(req, CurrentSettings())
+	if fieldErrs = append(fieldErrs, optErrs...); len(fieldErrs) > 0 {
+		return opts, &RequestError{Fields: fieldErrs}
+	}
+	if err := validateURL(req.URL); err != nil {
+		return opts, &RequestError{Fields: []models.FieldError{
+			{Field: "url", Message: fmt.Sprintf("must be an absolute http or https URL, got %q", req.URL)},
+		}}
+	}
+	return opts, nil
+}
@@ -0,0 +1,61 @@
+// Package apierror writes error responses: models.ErrorResponse as JSON, or an RFC 7807
+// problem when the client asks for application/problem+json.
+package apierror
+
+import (
+	"encoding/json"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/models"
+)
+
+// ProblemMediaType is the RFC 7807 media type.
+const ProblemMediaType = "application/problem+json"
+
+// Write writes resp with status.
+func Write(c *gin.Context, status int, resp models.ErrorResponse) {
+	if !wantsProblem(c.GetHeader("Accept")) {
+		c.JSON(status, resp)
+		return
+	}
+	body, _ := json.Marshal(Problem(c, status, resp)) // plain strings and ints, cannot fail
+	c.Data(status, ProblemMediaType, body)
+}
+
+// Abort writes resp with status and stops the handler chain.
+func Abort(c *gin.Context, status int, resp models.ErrorResponse) {
+	c.Abort()
+	Write(c, status, resp)
+}
+
+// Problem converts resp to a problem details object for the request in c.
+func Problem(c *gin.Context, status int, resp models.ErrorResponse) models.Problem {
+	return models.Problem{
+		Type:      models.ProblemTypePrefix + resp.Code,
+		Title:     resp.Error,
+		Status:    status,
+		Detail:    resp.Details,
+		Instance:  c.Request.URL.Path,
+		Code:      resp.Code,
+		Fields:    resp.Fields,
+		RequestID: c.GetString("requestID"),
+	}
+}
+
+// wantsProblem reports whether the Accept header lists the problem media type, unless
+// it refuses it with q=0.
+func wantsProblem(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ProblemMediaType {
+			continue
+		}
+		q, err := strconv.ParseFloat(params["q"], 64)
+		return err != nil || q > 0
+	}
+	return false
+}
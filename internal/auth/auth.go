@@ -0,0 +1,309 @@
+// Package auth validates API keys and request signatures and enforces per-key rate
+// limits and daily quotas, for both the REST and gRPC servers.
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/ratelimit"
+	"web-analyzer/pkg/api"
+	"web-analyzer/pkg/metrics"
+)
+
+// maxSignatureSkew is how far a signed request's timestamp may drift from the server clock.
+// Nonces are remembered for as long as their timestamp is accepted, so a signed request
+// cannot be replayed within the window either.
+const maxSignatureSkew = 5 * time.Minute
+
+// APIKey is one client credential. Clients authenticate either with the static Key
+// (X-API-Key or "Authorization: Bearer") or by signing requests with Secret.
+type APIKey struct {
+	ID         string  `json:"id"`
+	Key        string  `json:"key,omitempty"`
+	Secret     string  `json:"secret,omitempty"`
+	RateLimit  float64 `json:"rate_limit,omitempty"`  // requests per second, 0 = unlimited
+	Burst      int     `json:"burst,omitempty"`       // defaults to the rate limit rounded up
+	DailyQuota int     `json:"daily_quota,omitempty"` // analyses per UTC day, 0 = unlimited
+}
+
+// Config is the contents of the API key file.
+type Config struct {
+	Keys []APIKey `json:"keys"`
+}
+
+// LoadConfig reads API keys from a JSON file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse auth config: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, key := range cfg.Keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("auth config: every key needs an id")
+		}
+		if key.Key == "" && key.Secret == "" {
+			return nil, fmt.Errorf("auth config: key %q has neither a key nor a secret", key.ID)
+		}
+		if seen[key.ID] {
+			return nil, fmt.Errorf("auth config: duplicate key id %q", key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	return &cfg, nil
+}
+
+// Authenticator validates API credentials and enforces per-key rate limits and daily quotas.
+type Authenticator struct {
+	byID     map[string]*APIKey
+	byDigest map[[sha256.Size]byte]*APIKey
+	limiters map[string]ratelimit.Limiter
+	quotas   *quotaTracker
+	nonces   *nonceCache
+	now      func() time.Time
+}
+
+func New(cfg *Config) *Authenticator {
+	a := &Authenticator{
+		byID:     make(map[string]*APIKey),
+		byDigest: make(map[[sha256.Size]byte]*APIKey),
+		limiters: make(map[string]ratelimit.Limiter),
+		quotas:   newQuotaTracker(),
+		nonces:   newNonceCache(),
+		now:      time.Now,
+	}
+
+	for i := range cfg.Keys {
+		key := &cfg.Keys[i]
+		a.byID[key.ID] = key
+		if key.Key != "" {
+			a.byDigest[sha256.Sum256([]byte(key.Key))] = key
+		}
+		if key.RateLimit > 0 {
+			a.limiters[key.ID] = ratelimit.NewMemory(ratelimit.Policy{Rate: key.RateLimit, Burst: key.Burst})
+		}
+	}
+
+	return a
+}
+
+// LimitError is returned when a key is over its rate limit or daily quota.
+type LimitError struct {
+	Code       string // models.CodeRateLimited or models.CodeQuotaExceeded
+	Message    string
+	RetryAfter time.Duration // until the rate limit allows another request
+}
+
+func (e *LimitError) Error() string {
+	return e.Message
+}
+
+// Quota is a key's daily quota after a charge. Limit is zero for keys without one.
+type Quota struct {
+	Limit     int
+	Remaining int
+}
+
+// LookupKey returns the key whose static Key is presented.
+func (a *Authenticator) LookupKey(presented string) (*APIKey, error) {
+	if presented == "" {
+		return nil, fmt.Errorf("missing API key")
+	}
+
+	key, ok := a.byDigest[sha256.Sum256([]byte(presented))]
+	if !ok {
+		return nil, fmt.Errorf("invalid API key")
+	}
+	return key, nil
+}
+
+// Limiter returns the rate limiter of keyID, nil when the key is not rate limited.
+func (a *Authenticator) Limiter(keyID string) ratelimit.Limiter {
+	return a.limiters[keyID]
+}
+
+// AuthenticateAPIKey returns the ID of the key whose static Key is presented and applies
+// the key's rate limit, for callers that do not report the limiter state themselves,
+// such as the gRPC server. The ID is returned along with a *LimitError when the key is
+// rate limited. Daily quotas are charged separately with ChargeQuota.
+func (a *Authenticator) AuthenticateAPIKey(ctx context.Context, presented string) (string, error) {
+	key, err := a.LookupKey(presented)
+	if err != nil {
+		return "", err
+	}
+
+	limiter, ok := a.limiters[key.ID]
+	if !ok {
+		return key.ID, nil
+	}
+	result, err := limiter.Allow(ctx, key.ID)
+	if err != nil {
+		slog.Warn("rate limiter unavailable, allowing request", "key", key.ID, "error", err)
+		return key.ID, nil
+	}
+	if !result.Allowed {
+		return key.ID, &LimitError{
+			Code:       models.CodeRateLimited,
+			Message:    "rate limit exceeded",
+			RetryAfter: result.RetryAfter,
+		}
+	}
+	return key.ID, nil
+}
+
+// ChargeQuota counts n analyses against keyID's daily quota. When they do not all fit
+// nothing is counted and a *LimitError is returned.
+func (a *Authenticator) ChargeQuota(keyID string, n int) (Quota, error) {
+	key, ok := a.byID[keyID]
+	if !ok || key.DailyQuota <= 0 {
+		return Quota{}, nil
+	}
+
+	used, allowed := a.quotas.consume(key.ID, key.DailyQuota, n, a.now())
+	quota := Quota{Limit: key.DailyQuota, Remaining: max(key.DailyQuota-used, 0)}
+	if !allowed {
+		metrics.APIKeyRequests.WithLabelValues(key.ID, "quota_exceeded").Inc()
+		return quota, &LimitError{
+			Code:    models.CodeQuotaExceeded,
+			Message: fmt.Sprintf("daily quota exceeded: key %q allows %d analyses per day, %d remaining", key.ID, key.DailyQuota, quota.Remaining),
+		}
+	}
+
+	metrics.APIKeyAnalyses.WithLabelValues(key.ID).Add(float64(n))
+	return quota, nil
+}
+
+// VerifySignature checks the HMAC-SHA256 signature of r over the canonical request,
+// see api.SignRequest for the exact format, and rejects a nonce the key already used
+// within the timestamp window. The body is read, bounded like the analyze
+// request parser bounds it, and put back for the handler; an oversized body fails with
+// *http.MaxBytesError.
+func (a *Authenticator) VerifySignature(w http.ResponseWriter, r *http.Request) (*APIKey, error) {
+	key, ok := a.byID[r.Header.Get(api.HeaderKeyID)]
+	if !ok || key.Secret == "" {
+		return nil, fmt.Errorf("unknown signing key")
+	}
+
+	timestamp := r.Header.Get(api.HeaderTimestamp)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header", api.HeaderTimestamp)
+	}
+	skew := a.now().Sub(time.Unix(unix, 0))
+	if skew > maxSignatureSkew || skew < -maxSignatureSkew {
+		return nil, fmt.Errorf("request timestamp outside the allowed window")
+	}
+	nonce := r.Header.Get(api.HeaderNonce)
+	if nonce == "" || len(nonce) > api.MaxNonceLength {
+		return nil, fmt.Errorf("invalid %s header", api.HeaderNonce)
+	}
+
+	// The body is read before the signature proves anything, so it is bounded.
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, analysis.MaxRequestBodyBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body")
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	expected := api.Signature(key.Secret, r.Method, r.URL.RequestURI(), timestamp, nonce, body)
+	provided := r.Header.Get(api.HeaderSignature)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(provided)) != 1 {
+		return nil, fmt.Errorf("invalid request signature")
+	}
+	// Only verified nonces are recorded, so unsigned requests cannot fill the cache.
+	if !a.nonces.use(key.ID+"\n"+nonce, time.Unix(unix, 0).Add(maxSignatureSkew), a.now()) {
+		return nil, fmt.Errorf("request nonce already used")
+	}
+
+	return key, nil
+}
+
+// nonceCache remembers the nonces of verified signed requests until their timestamp
+// leaves the accepted window, in memory.
+type nonceCache struct {
+	mu        sync.Mutex
+	expiries  map[string]time.Time
+	nextSweep time.Time
+}
+
+func newNonceCache() *nonceCache {
+	return &nonceCache{expiries: make(map[string]time.Time)}
+}
+
+// use records nonce until expires and reports whether it was unused.
+func (n *nonceCache) use(nonce string, expires, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if now.After(n.nextSweep) {
+		for k, exp := range n.expiries {
+			if now.After(exp) {
+				delete(n.expiries, k)
+			}
+		}
+		n.nextSweep = now.Add(time.Minute)
+	}
+
+	if exp, ok := n.expiries[nonce]; ok && !now.After(exp) {
+		return false
+	}
+	n.expiries[nonce] = expires
+	return true
+}
+
+// quotaTracker counts uses per key per UTC day, in memory.
+type quotaTracker struct {
+	mu     sync.Mutex
+	day    string
+	counts map[string]int
+}
+
+func newQuotaTracker() *quotaTracker {
+	return &quotaTracker{counts: make(map[string]int)}
+}
+
+// consume counts n uses for keyID if they fit within limit, returning the uses so far.
+func (q *quotaTracker) consume(keyID string, limit, n int, now time.Time) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if day := now.UTC().Format(time.DateOnly); day != q.day {
+		q.day = day
+		q.counts = make(map[string]int)
+	}
+
+	if q.counts[keyID]+n > limit {
+		return q.counts[keyID], false
+	}
+	q.counts[keyID] += n
+	return q.counts[keyID], true
+}
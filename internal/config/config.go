@@ -0,0 +1,267 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"web-analyzer/internal/netguard"
+)
+
+// Config is the complete service configuration. Every setting can come from the config
+// file, a WEB_ANALYZER_* environment variable or a command line flag, see Load.
+//
+// The flag tag names the command line flag; the environment variable is derived from it
+// (max-links -> WEB_ANALYZER_MAX_LINKS).
+type Config struct {
+	Server   ServerConfig   `yaml:"server" toml:"server" reload:"restart"`
+	Analysis AnalysisConfig `yaml:"analysis" toml:"analysis"`
+	Network  NetworkConfig  `yaml:"network" toml:"network"`
+	Storage  StorageConfig  `yaml:"storage" toml:"storage" reload:"restart"`
+	Cache    CacheConfig    `yaml:"cache" toml:"cache" reload:"restart"`
+	Schedule ScheduleConfig `yaml:"schedule" toml:"schedule" reload:"restart"`
+	Webhook  WebhookConfig  `yaml:"webhook" toml:"webhook" reload:"restart"`
+	Log      LogConfig      `yaml:"log" toml:"log"`
+}
+
+// ServerConfig covers the listeners and the HTTP middleware. Changes only take
+// effect after a restart.
+type ServerConfig struct {
+	Port            int      `yaml:"port" toml:"port" flag:"port" usage:"Port for the HTTP server"`
+	DebugPort       int      `yaml:"debug_port" toml:"debug_port" flag:"debug-port" usage:"Debug server port for pprof, 0 disables it"`
+	GRPCPort        int      `yaml:"grpc_port" toml:"grpc_port" flag:"grpc-port" usage:"Port for the gRPC server, 0 disables it"`
+	Concurrency     int      `yaml:"concurrency" toml:"concurrency" flag:"concurrency" usage:"Maximum concurrency level"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" flag:"shutdown-timeout" usage:"Time allowed for in-flight requests on shutdown"`
+	AuthConfig      string   `yaml:"auth_config" toml:"auth_config" flag:"auth-config" usage:"Path to the API key file; the API is unauthenticated when empty"`
+	CORSOrigins     []string `yaml:"cors_origins" toml:"cors_origins" flag:"cors-origins" usage:"Comma-separated origins allowed to call the API with credentials"`
+	CORSMaxAge      Duration `yaml:"cors_max_age" toml:"cors_max_age" flag:"cors-max-age" usage:"How long browsers may cache CORS preflight responses"`
+	TrustedProxies  []string `yaml:"trusted_proxies" toml:"trusted_proxies" flag:"trusted-proxies" usage:"Comma-separated proxy CIDRs whose X-Forwarded-For is trusted"`
+	RateLimit       float64  `yaml:"rate_limit" toml:"rate_limit" flag:"rate-limit" usage:"Requests per second allowed per API key or client IP, 0 disables"`
+	RateBurst       int      `yaml:"rate_burst" toml:"rate_burst" flag:"rate-burst" usage:"Burst size for -rate-limit, defaults to the rate rounded up"`
+}
+
+// AnalysisConfig tunes page fetching, parsing and link checking. It is reloaded on SIGHUP.
+type AnalysisConfig struct {
+	Timeout      Duration `yaml:"timeout" toml:"timeout" flag:"analysis-timeout" usage:"Time allowed for a whole analysis, including link checks"`
+	FetchTimeout Duration `yaml:"fetch_timeout" toml:"fetch_timeout" flag:"fetch-timeout" usage:"HTTP client timeout for fetching the analyzed page"`
+	LinkTimeout  Duration `yaml:"link_timeout" toml:"link_timeout" flag:"link-timeout" usage:"Timeout for checking a single link"`
+	MaxRedirects int      `yaml:"max_redirects" toml:"max_redirects" flag:"max-redirects" usage:"Redirects followed when checking a link"`
+	Workers      int      `yaml:"workers" toml:"workers" flag:"link-workers" usage:"Concurrent link checks per analysis"`
+	LinkBuffer   int      `yaml:"link_buffer" toml:"link_buffer" flag:"link-buffer" usage:"Links queued between the parser and the link checkers"`
+	UserAgent    string   `yaml:"user_agent" toml:"user_agent" flag:"user-agent" usage:"User-Agent sent when fetching pages and checking links"`
+	MaxBodyBytes int64    `yaml:"max_body_bytes" toml:"max_body_bytes" flag:"max-body-bytes" usage:"Maximum decoded page size read per analysis"`
+	MaxDOMNodes  int      `yaml:"max_dom_nodes" toml:"max_dom_nodes" flag:"max-dom-nodes" usage:"Maximum element nodes analyzed per page"`
+	MaxLinks     int      `yaml:"max_links" toml:"max_links" flag:"max-links" usage:"Maximum links extracted and checked per page"`
+	MaxDepth     int      `yaml:"max_depth" toml:"max_depth" flag:"max-depth" usage:"Maximum element nesting depth analyzed"`
+}
+
+// NetworkConfig is the outbound network policy. It is reloaded on SIGHUP.
+type NetworkConfig struct {
+	AllowCIDRs []string `yaml:"allow_cidrs" toml:"allow_cidrs" flag:"allow-cidrs" usage:"Comma-separated CIDRs outbound fetches may reach despite the SSRF block list"`
+	DenyCIDRs  []string `yaml:"deny_cidrs" toml:"deny_cidrs" flag:"deny-cidrs" usage:"Comma-separated CIDRs outbound fetches may never reach"`
+
+	MaxIdleConns          int      `yaml:"max_idle_conns" toml:"max_idle_conns" flag:"max-idle-conns" usage:"Idle outbound connections kept open across all hosts, 0 is unlimited"`
+	MaxIdleConnsPerHost   int      `yaml:"max_idle_conns_per_host" toml:"max_idle_conns_per_host" flag:"max-idle-conns-per-host" usage:"Idle outbound connections kept open per host, so link checks on one site reuse them"`
+	IdleConnTimeout       Duration `yaml:"idle_conn_timeout" toml:"idle_conn_timeout" flag:"idle-conn-timeout" usage:"How long an idle outbound connection is kept open, 0 keeps it until the host closes it"`
+	DialTimeout           Duration `yaml:"dial_timeout" toml:"dial_timeout" flag:"dial-timeout" usage:"Timeout for opening an outbound TCP connection"`
+	TLSHandshakeTimeout   Duration `yaml:"tls_handshake_timeout" toml:"tls_handshake_timeout" flag:"tls-handshake-timeout" usage:"Timeout for the TLS handshake of an outbound connection, 0 is unlimited"`
+	ResponseHeaderTimeout Duration `yaml:"response_header_timeout" toml:"response_header_timeout" flag:"response-header-timeout" usage:"Time to wait for response headers after sending a request, 0 leaves it to the client timeout"`
+	DisableHTTP2          bool     `yaml:"disable_http2" toml:"disable_http2" flag:"disable-http2" usage:"Only speak HTTP/1.1 to analyzed sites"`
+}
+
+// StorageConfig selects where analysis results are kept and for how long. Changes
+// only take effect after a restart.
+type StorageConfig struct {
+	Path       string   `yaml:"path" toml:"path" flag:"storage-path" usage:"File for stored analysis results; results are kept in memory when empty"`
+	MaxAge     Duration `yaml:"max_age" toml:"max_age" flag:"storage-max-age" usage:"Stored results older than this are dropped, 0 keeps them forever"`
+	MaxPerURL  int      `yaml:"max_per_url" toml:"max_per_url" flag:"storage-max-per-url" usage:"Stored results kept per URL, 0 is unlimited"`
+	MaxRecords int      `yaml:"max_records" toml:"max_records" flag:"storage-max-records" usage:"Stored results kept in total, 0 is unlimited"`
+}
+
+// CacheConfig controls the analyze response cache. Changes only take effect after a restart.
+type CacheConfig struct {
+	MaxEntries int      `yaml:"max_entries" toml:"max_entries" flag:"cache-max-entries" usage:"Analyses kept in the response cache, 0 disables it"`
+	DefaultTTL Duration `yaml:"default_ttl" toml:"default_ttl" flag:"cache-default-ttl" usage:"How long a result is fresh when the page sends no max-age or Expires"`
+	MaxAge     Duration `yaml:"max_age" toml:"max_age" flag:"cache-max-age" usage:"Cached results are rerun after this long, even if the page revalidates"`
+}
+
+// ScheduleConfig controls recurring analyses. Changes only take effect after a restart.
+type ScheduleConfig struct {
+	JobsPath      string   `yaml:"jobs_path" toml:"jobs_path" flag:"schedule-jobs-path" usage:"File schedules are persisted to; schedules are kept in memory when empty"`
+	MaxConcurrent int      `yaml:"max_concurrent" toml:"max_concurrent" flag:"schedule-max-concurrent" usage:"Scheduled analyses running at once"`
+	RunTimeout    Duration `yaml:"run_timeout" toml:"run_timeout" flag:"schedule-run-timeout" usage:"Time allowed for one scheduled analysis"`
+}
+
+// WebhookConfig controls outgoing webhook deliveries. Changes only take effect after a restart.
+type WebhookConfig struct {
+	Secret         string   `yaml:"secret" toml:"secret" flag:"webhook-secret" usage:"Shared secret webhook payloads are signed with"`
+	Timeout        Duration `yaml:"timeout" toml:"timeout" flag:"webhook-timeout" usage:"HTTP client timeout for one webhook delivery attempt"`
+	MaxAttempts    int      `yaml:"max_attempts" toml:"max_attempts" flag:"webhook-max-attempts" usage:"Delivery attempts before a webhook is given up"`
+	InitialBackoff Duration `yaml:"initial_backoff" toml:"initial_backoff" flag:"webhook-initial-backoff" usage:"Delay before the first redelivery, doubled after every attempt"`
+	MaxBackoff     Duration `yaml:"max_backoff" toml:"max_backoff" flag:"webhook-max-backoff" usage:"Longest delay between redeliveries"`
+	LogSize        int      `yaml:"log_size" toml:"log_size" flag:"webhook-log-size" usage:"Recent deliveries kept for the delivery log endpoint"`
+}
+
+// LogConfig is reloaded on SIGHUP.
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level" flag:"log-level" usage:"Log level (debug, info, warn, error)"`
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			DebugPort:       6060,
+			Concurrency:     runtime.NumCPU(),
+			ShutdownTimeout: Duration(10 * time.Second),
+			CORSMaxAge:      Duration(12 * time.Hour),
+		},
+		Analysis: AnalysisConfig{
+			Timeout:      Duration(30 * time.Second),
+			FetchTimeout: Duration(10 * time.Second),
+			LinkTimeout:  Duration(5 * time.Second),
+			MaxRedirects: 10,
+			Workers:      runtime.NumCPU() * 2,
+			LinkBuffer:   100,
+			UserAgent:    "WebAnalyzer/1.0",
+			MaxBodyBytes: 10 << 20,
+			MaxDOMNodes:  200_000,
+			MaxLinks:     5_000,
+			MaxDepth:     512,
+		},
+		Network: NetworkConfig{
+			MaxIdleConns:        200,
+			MaxIdleConnsPerHost: 32,
+			IdleConnTimeout:     Duration(90 * time.Second),
+			DialTimeout:         Duration(10 * time.Second),
+			TLSHandshakeTimeout: Duration(10 * time.Second),
+		},
+		Storage: StorageConfig{
+			MaxAge:     Duration(30 * 24 * time.Hour),
+			MaxPerURL:  100,
+			MaxRecords: 10_000,
+		},
+		Cache: CacheConfig{
+			MaxEntries: 1000,
+			DefaultTTL: Duration(5 * time.Minute),
+			MaxAge:     Duration(time.Hour),
+		},
+		Schedule: ScheduleConfig{
+			MaxConcurrent: 2,
+			RunTimeout:    Duration(2 * time.Minute),
+		},
+		Webhook: WebhookConfig{
+			Timeout:        Duration(10 * time.Second),
+			MaxAttempts:    5,
+			InitialBackoff: Duration(time.Second),
+			MaxBackoff:     Duration(time.Minute),
+			LogSize:        1000,
+		},
+		Log: LogConfig{Level: "info"},
+	}
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	s := c.Server
+	check(s.Port > 0 && s.Port <= 65535, "server.port must be between 1 and 65535, got %d", s.Port)
+	check(s.DebugPort >= 0 && s.DebugPort <= 65535, "server.debug_port must be between 0 and 65535, got %d", s.DebugPort)
+	check(s.GRPCPort >= 0 && s.GRPCPort <= 65535, "server.grpc_port must be between 0 and 65535, got %d", s.GRPCPort)
+	check(s.Concurrency > 0, "server.concurrency must be positive, got %d", s.Concurrency)
+	check(s.ShutdownTimeout > 0, "server.shutdown_timeout must be positive, got %s", s.ShutdownTimeout)
+	check(s.CORSMaxAge >= 0, "server.cors_max_age must not be negative, got %s", s.CORSMaxAge)
+	check(s.RateLimit >= 0, "server.rate_limit must not be negative, got %g", s.RateLimit)
+	check(s.RateBurst >= 0, "server.rate_burst must not be negative, got %d", s.RateBurst)
+	for _, origin := range s.CORSOrigins {
+		u, err := url.Parse(origin)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == "",
+			"server.cors_origins: %q is not an http(s) origin", origin)
+	}
+	for _, proxy := range s.TrustedProxies {
+		_, prefixErr := netip.ParsePrefix(proxy)
+		_, addrErr := netip.ParseAddr(proxy)
+		check(prefixErr == nil || addrErr == nil, "server.trusted_proxies: %q is not an IP or CIDR", proxy)
+	}
+
+	a := c.Analysis
+	check(a.Timeout > 0, "analysis.timeout must be positive, got %s", a.Timeout)
+	check(a.FetchTimeout > 0, "analysis.fetch_timeout must be positive, got %s", a.FetchTimeout)
+	check(a.LinkTimeout > 0, "analysis.link_timeout must be positive, got %s", a.LinkTimeout)
+	check(a.MaxRedirects >= 0, "analysis.max_redirects must not be negative, got %d", a.MaxRedirects)
+	check(a.Workers > 0, "analysis.workers must be positive, got %d", a.Workers)
+	check(a.LinkBuffer >= 0, "analysis.link_buffer must not be negative, got %d", a.LinkBuffer)
+	check(strings.TrimSpace(a.UserAgent) != "", "analysis.user_agent must not be empty")
+
+	st := c.Storage
+	check(st.MaxAge >= 0, "storage.max_age must not be negative, got %s", st.MaxAge)
+	check(st.MaxPerURL >= 0, "storage.max_per_url must not be negative, got %d", st.MaxPerURL)
+	check(st.MaxRecords >= 0, "storage.max_records must not be negative, got %d", st.MaxRecords)
+
+	ca := c.Cache
+	check(ca.MaxEntries >= 0, "cache.max_entries must not be negative, got %d", ca.MaxEntries)
+	check(ca.DefaultTTL >= 0, "cache.default_ttl must not be negative, got %s", ca.DefaultTTL)
+	check(ca.MaxEntries == 0 || ca.MaxAge > 0, "cache.max_age must be positive, got %s", ca.MaxAge)
+
+	sc := c.Schedule
+	check(sc.MaxConcurrent >= 1, "schedule.max_concurrent must be at least 1, got %d", sc.MaxConcurrent)
+	check(sc.RunTimeout > 0, "schedule.run_timeout must be positive, got %s", sc.RunTimeout)
+
+	wh := c.Webhook
+	check(wh.Timeout > 0, "webhook.timeout must be positive, got %s", wh.Timeout)
+	check(wh.MaxAttempts >= 1, "webhook.max_attempts must be at least 1, got %d", wh.MaxAttempts)
+	check(wh.InitialBackoff > 0, "webhook.initial_backoff must be positive, got %s", wh.InitialBackoff)
+	check(wh.LogSize >= 1, "webhook.log_size must be at least 1, got %d", wh.LogSize)
+	check(wh.MaxBackoff >= wh.InitialBackoff, "webhook.max_backoff must not be less than webhook.initial_backoff, got %s", wh.MaxBackoff)
+
+	n := c.Network
+	check(n.MaxIdleConns >= 0, "network.max_idle_conns must not be negative, got %d", n.MaxIdleConns)
+	check(n.MaxIdleConnsPerHost > 0, "network.max_idle_conns_per_host must be positive, got %d", n.MaxIdleConnsPerHost)
+	check(n.IdleConnTimeout >= 0, "network.idle_conn_timeout must not be negative, got %s", n.IdleConnTimeout)
+	check(n.DialTimeout > 0, "network.dial_timeout must be positive, got %s", n.DialTimeout)
+	check(n.TLSHandshakeTimeout >= 0, "network.tls_handshake_timeout must not be negative, got %s", n.TLSHandshakeTimeout)
+	check(n.ResponseHeaderTimeout >= 0, "network.response_header_timeout must not be negative, got %s", n.ResponseHeaderTimeout)
+	if _, err := netguard.New(c.Network.AllowCIDRs, c.Network.DenyCIDRs); err != nil {
+		errs = append(errs, fmt.Errorf("network: %w", err))
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level must be one of debug, info, warn, error, got %q", c.Log.Level))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Duration is a time.Duration written as a Go duration string ("30s", "1m30s") in
+// config files, environment variables and flags.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(strings.TrimSpace(string(text)))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
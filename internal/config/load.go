@@ -0,0 +1,217 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to the upper-cased flag name to form a setting's environment variable.
+const EnvPrefix = "WEB_ANALYZER_"
+
+// Load builds the configuration from, in increasing order of precedence: the defaults,
+// the config file, WEB_ANALYZER_* environment variables and the command line flags in args.
+// The config file is named by -config or WEB_ANALYZER_CONFIG and may be YAML or TOML.
+// The result is validated.
+func Load(args []string) (*Config, error) {
+	flagged := Default()
+	fs := flag.NewFlagSet("web-analyzer", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(EnvPrefix+"CONFIG"), "Path to a YAML or TOML config file")
+	for _, f := range settings(flagged) {
+		fs.Var(&settingValue{f.value}, f.flag, f.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if *configPath != "" {
+		if err := loadFile(*configPath, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	target := settings(cfg)
+	for i, f := range settings(flagged) {
+		if explicit[f.flag] {
+			target[i].value.Set(f.value)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// RestartRequired lists the settings that differ in next but cannot be applied without a restart.
+func (c *Config) RestartRequired(next *Config) []string {
+	var changed []string
+	nextSettings := settings(next)
+	for i, s := range settings(c) {
+		if s.restart && !reflect.DeepEqual(s.value.Interface(), nextSettings[i].value.Interface()) {
+			changed = append(changed, s.path)
+		}
+	}
+	return changed
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("unsupported config file format %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	return nil
+}
+
+func applyEnv(cfg *Config) error {
+	for _, s := range settings(cfg) {
+		name := envName(s.flag)
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromString(s.value, raw); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func envName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// setting is one leaf field of Config.
+type setting struct {
+	path    string // section.key as written in the config file
+	flag    string
+	usage   string
+	restart bool
+	value   reflect.Value
+}
+
+// settings lists every tagged field of cfg in declaration order.
+func settings(cfg *Config) []setting {
+	var out []setting
+	root := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		sectionField := root.Type().Field(i)
+		section := root.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			out = append(out, setting{
+				path:    sectionField.Tag.Get("yaml") + "." + field.Tag.Get("yaml"),
+				flag:    field.Tag.Get("flag"),
+				usage:   field.Tag.Get("usage"),
+				restart: sectionField.Tag.Get("reload") == "restart",
+				value:   section.Field(j),
+			})
+		}
+	}
+	return out
+}
+
+var durationType = reflect.TypeOf(Duration(0))
+
+func setFromString(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		var d Duration
+		if err := d.UnmarshalText([]byte(raw)); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	raw = strings.TrimSpace(raw)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// settingValue adapts a Config field to flag.Value.
+type settingValue struct {
+	v reflect.Value
+}
+
+func (s *settingValue) String() string {
+	if !s.v.IsValid() {
+		return ""
+	}
+	if items, ok := s.v.Interface().([]string); ok {
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(s.v.Interface())
+}
+
+func (s *settingValue) Set(raw string) error {
+	return setFromString(s.v, raw)
+}
+
+// IsBoolFlag lets boolean settings be passed as a bare -flag.
+func (s *settingValue) IsBoolFlag() bool {
+	return s.v.IsValid() && s.v.Kind() == reflect.Bool
+}
@@ -0,0 +1,31 @@
+package config
+
+import "sync"
+
+// Reloader applies configuration reloads and remembers the last one applied, so every
+// reload is compared with the configuration before it rather than the startup one.
+type Reloader struct {
+	mu      sync.Mutex
+	current *Config
+	apply   func(*Config) error
+}
+
+// NewReloader starts from the running configuration current. apply puts the reloadable
+// settings of a new configuration into effect.
+func NewReloader(current *Config, apply func(*Config) error) *Reloader {
+	return &Reloader{current: current, apply: apply}
+}
+
+// Reload applies next and returns the settings it changes that need a restart to take
+// effect. When apply fails the previous configuration stays current.
+func (r *Reloader) Reload(next *Config) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.apply(next); err != nil {
+		return nil, err
+	}
+	pending := r.current.RestartRequired(next)
+	r.current = next
+	return pending, nil
+}
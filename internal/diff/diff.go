@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/pkg/api"
+)
+
+// The report types are defined in pkg/api, which clients import without the server.
+type (
+	Report           = api.Report
+	Snapshot         = api.Snapshot
+	ValueChange      = api.ValueChange
+	LoginFormChange  = api.LoginFormChange
+	MetaTagChanges   = api.MetaTagChanges
+	HeadingChange    = api.HeadingChange
+	LinkChanges      = api.LinkChanges
+	LinkStatusChange = api.LinkStatusChange
+	LinkCounts       = api.LinkCounts
+	CountChange      = api.CountChange
+)
+
+// Compare diffs the analyses stored in from and to.
+func Compare(from, to *models.AnalysisRecord) *Report {
+	a, b := from.Analysis, to.Analysis
+
+	r := &Report{
+		URL:  to.URL,
+		From: Snapshot{ID: from.ID, CreatedAt: from.CreatedAt},
+		To:   Snapshot{ID: to.ID, CreatedAt: to.CreatedAt},
+	}
+
+	r.Title = valueChange(a.Title, b.Title)
+	r.HTMLVersion = valueChange(a.HTMLVersion, b.HTMLVersion)
+	if a.HasLoginForm != b.HasLoginForm {
+		r.LoginForm = &LoginFormChange{Appeared: b.HasLoginForm, Disappeared: a.HasLoginForm}
+	}
+	r.MetaTags = compareMetaTags(a.MetaTags, b.MetaTags)
+	r.Headings = compareHeadings(a.Headings, b.Headings)
+	r.Links = compareLinks(a, b)
+
+	r.Changed = r.Title != nil || r.HTMLVersion != nil || r.LoginForm != nil ||
+		len(r.MetaTags.Added)+len(r.MetaTags.Removed)+len(r.MetaTags.Changed) > 0 ||
+		len(r.Headings) > 0 ||
+		len(r.Links.Added)+len(r.Links.Removed)+len(r.Links.NowBroken)+len(r.Links.Fixed) > 0
+
+	return r
+}
+
+func valueChange(from, to string) *ValueChange {
+	if from == to {
+		return nil
+	}
+	return &ValueChange{From: from, To: to}
+}
+
+func compareMetaTags(from, to map[string]string) MetaTagChanges {
+	var c MetaTagChanges
+	for name, value := range to {
+		old, ok := from[name]
+		switch {
+		case !ok:
+			if c.Added == nil {
+				c.Added = make(map[string]string)
+			}
+			c.Added[name] = value
+		case old != value:
+			if c.Changed == nil {
+				c.Changed = make(map[string]ValueChange)
+			}
+			c.Changed[name] = ValueChange{From: old, To: value}
+		}
+	}
+	for name, value := range from {
+		if _, ok := to[name]; !ok {
+			if c.Removed == nil {
+				c.Removed = make(map[string]string)
+			}
+			c.Removed[name] = value
+		}
+	}
+	return c
+}
+
+func compareHeadings(from, to map[string]int) []HeadingChange {
+	var changes []HeadingChange
+	for _, level := range []string{"h1", "h2", "h3", "h4", "h5", "h6"} {
+		if from[level] != to[level] {
+			changes = append(changes, HeadingChange{Level: level, From: from[level], To: to[level]})
+		}
+	}
+	return changes
+}
+
+func compareLinks(a, b *models.PageAnalysis) LinkChanges {
+	c := LinkChanges{
+		Counts: LinkCounts{
+			Internal: CountChange{From: a.InternalLinks, To: b.InternalLinks},
+			External: CountChange{From: a.ExternalLinks, To: b.ExternalLinks},
+			Broken:   CountChange{From: a.BrokenLinks, To: b.BrokenLinks},
+		},
+	}
+
+	fromComplete, toComplete := checkedAll(a), checkedAll(b)
+	if !fromComplete || !toComplete {
+		c.Partial = true
+		var reasons []string
+		if !fromComplete {
+			reasons = append(reasons, "from "+uncheckedReason(a))
+		}
+		if !toComplete {
+			reasons = append(reasons, "to "+uncheckedReason(b))
+		}
+		c.PartialReason = strings.Join(reasons, "; ")
+	}
+
+	for _, link := range slices.Sorted(maps.Keys(b.LinksStatus)) {
+		status := b.LinksStatus[link]
+		old, existed := a.LinksStatus[link]
+		if !existed && !fromComplete {
+			// From may have found the link without checking it.
+			continue
+		}
+		if !existed {
+			c.Added = append(c.Added, link)
+		}
+		switch {
+		case isBroken(status) && (!existed || !isBroken(old)):
+			c.NowBroken = append(c.NowBroken, LinkStatusChange{URL: link, From: old, To: status})
+		case existed && isBroken(old) && !isBroken(status):
+			c.Fixed = append(c.Fixed, LinkStatusChange{URL: link, From: old, To: status})
+		}
+	}
+	if toComplete {
+		for _, link := range slices.Sorted(maps.Keys(a.LinksStatus)) {
+			if _, ok := b.LinksStatus[link]; !ok {
+				c.Removed = append(c.Removed, link)
+			}
+		}
+	}
+	return c
+}
+
+// checkedAll reports whether the analysis checked every link on the page, so a link
+// missing from its LinksStatus was not on the page.
+func checkedAll(p *models.PageAnalysis) bool {
+	return !p.Truncated && !p.Cancelled && p.SkippedLinks == 0
+}
+
+func uncheckedReason(p *models.PageAnalysis) string {
+	switch {
+	case p.Truncated:
+		return "was truncated: " + p.TruncatedReason
+	case p.Cancelled:
+		return "was cancelled before every link was checked"
+	default:
+		return fmt.Sprintf("skipped %d link(s)", p.SkippedLinks)
+	}
+}
+
+// isBroken matches the statuses LinkChecker.Result reports for broken links. Links blocked by
+// the network policy were never requested, so they are not considered broken.
+func isBroken(status string) bool {
+	return strings.HasPrefix(status, "Status: ") || strings.HasPrefix(status, "Error: ")
+}
@@ -0,0 +1,21 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// writeCSV writes one row per checked link: the link, its state and the recorded status.
+func writeCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"page_url", "link_url", "state", "status"}); err != nil {
+		return err
+	}
+	for _, l := range r.links() {
+		if err := cw.Write([]string{r.URL, l.URL, l.State, l.Status}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
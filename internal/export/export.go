@@ -0,0 +1,140 @@
+// Package export renders analysis results in formats other tools consume: CSV for
+// spreadsheets, a self-contained HTML report, JUnit XML for CI and SARIF for
+// code-scanning dashboards.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/utils"
+)
+
+// Format is an output format name, as accepted by the format query parameter and the
+// CLI -format flag.
+type Format string
+
+const (
+	JSON  Format = "json"
+	CSV   Format = "csv"
+	HTML  Format = "html"
+	JUnit Format = "junit"
+	SARIF Format = "sarif"
+)
+
+// Formats lists every format, the default first.
+var Formats = []Format{JSON, CSV, HTML, JUnit, SARIF}
+
+var mediaTypes = map[Format]string{
+	JSON:  "application/json",
+	CSV:   "text/csv",
+	HTML:  "text/html",
+	JUnit: "application/xml",
+	SARIF: "application/sarif+json",
+}
+
+// ParseFormat looks up a format by name, ignoring case.
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(name))
+	if _, ok := mediaTypes[f]; !ok {
+		return "", fmt.Errorf("unknown format %q, must be one of %s", name, FormatNames())
+	}
+	return f, nil
+}
+
+// FormatNames lists the format names for messages, e.g. "json, csv, html, junit, sarif".
+func FormatNames() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// MediaType is the format's MIME type, used for content negotiation.
+func (f Format) MediaType() string {
+	return mediaTypes[f]
+}
+
+// ContentType is the Content-Type header value of a response in this format.
+func (f Format) ContentType() string {
+	if f == CSV || f == HTML || f == JUnit {
+		return f.MediaType() + "; charset=utf-8"
+	}
+	return f.MediaType()
+}
+
+// Report is one analysis together with what identifies it.
+type Report struct {
+	URL        string
+	ResultID   string // empty when the result was not stored
+	AnalyzedAt time.Time
+	Analysis   *models.PageAnalysis
+}
+
+// Write renders r in format f.
+func Write(w io.Writer, f Format, r Report) error {
+	switch f {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r.Analysis)
+	case CSV:
+		return writeCSV(w, r)
+	case HTML:
+		return writeHTML(w, r)
+	case JUnit:
+		return writeJUnit(w, r)
+	case SARIF:
+		return writeSARIF(w, r)
+	}
+	return fmt.Errorf("unknown format %q", f)
+}
+
+// Link states derived from the statuses the link checker records.
+const (
+	LinkOK                 = "ok"
+	LinkBroken             = "broken"
+	LinkExpiredCertificate = "expired_certificate" // also counted as broken
+	LinkBlocked            = "blocked"             // refused by the network policy, never requested
+)
+
+// link is one checked link of a report.
+type link struct {
+	URL    string
+	State  string
+	Status string // as recorded by the link checker, e.g. "Status: 404 Not Found"
+}
+
+func (l link) broken() bool {
+	return l.State == LinkBroken || l.State == LinkExpiredCertificate
+}
+
+// links returns the checked links of r sorted by URL.
+func (r Report) links() []link {
+	links := make([]link, 0, len(r.Analysis.LinksStatus))
+	for _, u := range slices.Sorted(maps.Keys(r.Analysis.LinksStatus)) {
+		links = append(links, link{URL: u, State: LinkState(r.Analysis.LinksStatus[u]), Status: r.Analysis.LinksStatus[u]})
+	}
+	return links
+}
+
+// LinkState classifies a status recorded by the link checker as one of the Link states.
+func LinkState(status string) string {
+	switch {
+	case status == "OK":
+		return LinkOK
+	case strings.HasPrefix(status, "Blocked: "):
+		return LinkBlocked
+	case strings.HasPrefix(status, utils.ExpiredCertStatusPrefix):
+		return LinkExpiredCertificate
+	default:
+		return LinkBroken
+	}
+}
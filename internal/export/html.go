@@ -0,0 +1,123 @@
+package export
+
+import (
+	"html/template"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+// writeHTML writes a self-contained report: styles are inline and nothing is loaded
+// from elsewhere, so the file can be attached or archived as is.
+func writeHTML(w io.Writer, r Report) error {
+	a := r.Analysis
+	data := htmlReport{Report: r, Links: r.links()}
+	for _, l := range data.Links {
+		if l.broken() {
+			data.Broken = append(data.Broken, l)
+		}
+	}
+	for _, level := range slices.Sorted(maps.Keys(a.Headings)) {
+		data.Headings = append(data.Headings, headingCount{Level: level, Count: a.Headings[level]})
+	}
+	for _, name := range slices.Sorted(maps.Keys(a.MetaTags)) {
+		data.MetaTags = append(data.MetaTags, metaTag{Name: name, Content: a.MetaTags[name]})
+	}
+	return reportTemplate.Execute(w, data)
+}
+
+type htmlReport struct {
+	Report
+	Links    []link
+	Broken   []link
+	Headings []headingCount
+	MetaTags []metaTag
+}
+
+type headingCount struct {
+	Level string
+	Count int
+}
+
+type metaTag struct {
+	Name    string
+	Content string
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"datetime": func(t time.Time) string { return t.UTC().Format(time.RFC1123) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Analysis of {{.URL}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 60rem; padding: 0 1rem; color: #222; }
+h1 { font-size: 1.4rem; word-break: break-all; }
+h2 { font-size: 1.1rem; margin-top: 2rem; border-bottom: 1px solid #ddd; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .3rem .6rem; border-bottom: 1px solid #eee; vertical-align: top; word-break: break-all; }
+th { width: 14rem; word-break: normal; }
+.ok { color: #1a7f37; } .broken, .expired_certificate { color: #cf222e; } .blocked { color: #9a6700; }
+.warning { background: #fff8c5; padding: .6rem; border: 1px solid #d4a72c; }
+.meta { color: #666; }
+</style>
+</head>
+<body>
+<h1>Analysis of <a href="{{.URL}}">{{.URL}}</a></h1>
+<p class="meta">{{if not .AnalyzedAt.IsZero}}Analyzed {{datetime .AnalyzedAt}}{{end}}{{if .ResultID}} &middot; result {{.ResultID}}{{end}}</p>
+{{with .Analysis}}{{if .Truncated}}<p class="warning">The analysis is partial: {{.TruncatedReason}}</p>{{end}}
+{{if .Cancelled}}<p class="warning">The analysis deadline passed before every link was checked; the rest are counted as skipped.</p>{{end}}
+<h2>Summary</h2>
+<table>
+<tr><th>Title</th><td>{{.Title}}</td></tr>
+<tr><th>HTML version</th><td>{{.HTMLVersion}}{{if .DocumentMode}} ({{.DocumentMode}}){{end}}</td></tr>
+{{if .Charset}}<tr><th>Charset</th><td>{{.Charset}}</td></tr>{{end}}
+<tr><th>Internal links</th><td>{{.InternalLinks}}</td></tr>
+<tr><th>External links</th><td>{{.ExternalLinks}}</td></tr>
+<tr><th>Broken links</th><td{{if .BrokenLinks}} class="broken"{{end}}>{{.BrokenLinks}}</td></tr>
+{{if .BlockedLinks}}<tr><th>Blocked links</th><td>{{.BlockedLinks}}</td></tr>{{end}}
+{{if .SkippedLinks}}<tr><th>Unchecked links</th><td>{{.SkippedLinks}}</td></tr>{{end}}
+<tr><th>Login form</th><td>{{if .HasLoginForm}}yes{{else}}no{{end}}</td></tr>
+{{if .PageSize}}<tr><th>Page size</th><td>{{.PageSize}} bytes{{if and .ContentEncoding (ne .ContentEncoding "identity")}} ({{.CompressedSize}} bytes {{.ContentEncoding}}){{end}}</td></tr>{{end}}
+{{if .LoadTime}}<tr><th>Load time</th><td>{{.LoadTime}} ms</td></tr>{{end}}
+{{if .AnalysisDuration}}<tr><th>Analysis duration</th><td>{{.AnalysisDuration}}</td></tr>{{end}}
+</table>
+{{end}}
+{{if .Broken}}<h2>Broken links</h2>
+<table>
+{{range .Broken}}<tr><td><a href="{{.URL}}">{{.URL}}</a></td><td class="{{.State}}">{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Headings}}<h2>Headings</h2>
+<table>
+{{range .Headings}}<tr><th>{{.Level}}</th><td>{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Analysis.TLS}}<h2>TLS</h2>
+<table>
+<tr><th>Version</th><td>{{.Version}}</td></tr>
+<tr><th>Cipher suite</th><td>{{.CipherSuite}}</td></tr>
+<tr><th>Days remaining</th><td{{if or .Expired .HostnameMismatch .SelfSigned}} class="broken"{{end}}>{{.DaysRemaining}}{{if .Expired}}, expired{{end}}{{if .HostnameMismatch}}, host name mismatch{{end}}{{if .SelfSigned}}, self-signed{{end}}</td></tr>
+{{range .Certificates}}<tr><th>Certificate</th><td>{{.Subject}}<br><span class="meta">issued by {{.Issuer}}, valid until {{datetime .NotAfter}}</span></td></tr>
+{{end}}</table>
+{{end}}
+{{with .Analysis.MixedContent.Items}}<h2>Mixed content</h2>
+<table>
+{{range .}}<tr><th>{{.Type}}</th><td>&lt;{{.Element}} {{.Attribute}}&gt; {{.URL}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .MetaTags}}<h2>Meta tags</h2>
+<table>
+{{range .MetaTags}}<tr><th>{{.Name}}</th><td>{{.Content}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Links}}<h2>Checked links</h2>
+<table>
+{{range .Links}}<tr><td><a href="{{.URL}}">{{.URL}}</a></td><td class="{{.State}}">{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
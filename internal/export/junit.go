@@ -0,0 +1,98 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// writeJUnit writes one test case per checked link, so CI fails on broken links. Links
+// blocked by the network policy are reported as skipped.
+func writeJUnit(w io.Writer, r Report) error {
+	suite := junitTestSuite{
+		Name: r.URL,
+		Time: fmt.Sprintf("%.3f", (time.Duration(r.Analysis.LoadTime) * time.Millisecond).Seconds()),
+		Properties: []junitProperty{
+			{Name: "title", Value: r.Analysis.Title},
+			{Name: "html_version", Value: r.Analysis.HTMLVersion},
+		},
+	}
+	if !r.AnalyzedAt.IsZero() {
+		suite.Timestamp = r.AnalyzedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+	if r.ResultID != "" {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "result_id", Value: r.ResultID})
+	}
+
+	for _, l := range r.links() {
+		tc := junitTestCase{ClassName: "links", Name: l.URL, Time: "0"}
+		switch {
+		case l.broken():
+			tc.Failure = &junitMessage{Message: l.Status, Type: l.State}
+			suite.Failures++
+		case l.State == LinkBlocked:
+			tc.Skipped = &junitMessage{Message: l.Status}
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	suite.Tests = len(suite.TestCases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err := enc.Encode(junitTestSuites{
+		Name:     "web-analyzer",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"web-analyzer/internal/models"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "web-analyzer"
+)
+
+// SARIF rule ids.
+const (
+	RuleBrokenLink        = "broken-link"
+	RuleLinkCertificate   = "link-certificate-expired"
+	RulePageCertificate   = "page-certificate"
+	RuleMixedContent      = "mixed-content"
+	RuleAnalysisTruncated = "analysis-truncated"
+)
+
+const (
+	levelError   = "error"
+	levelWarning = "warning"
+	levelNote    = "note"
+
+	fingerprintKey = "webAnalyzerFinding/v1"
+)
+
+var sarifRules = []sarifRule{
+	newSARIFRule(RuleBrokenLink, levelError, "A link on the page answered with an error status or could not be requested."),
+	newSARIFRule(RuleLinkCertificate, levelError, "A link on the page points to a host whose TLS certificate has expired."),
+	newSARIFRule(RulePageCertificate, levelError, "The page's TLS certificate is expired, self-signed or does not match the host name."),
+	newSARIFRule(RuleMixedContent, levelWarning, "An HTTPS page references insecure http:// resources."),
+	newSARIFRule(RuleAnalysisTruncated, levelNote, "The page exceeded a parse limit, so the analysis is partial."),
+}
+
+func newSARIFRule(id, level, description string) sarifRule {
+	return sarifRule{ID: id, ShortDescription: sarifText{description}, DefaultConfiguration: sarifConfig{level}}
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool         `json:"tool"`
+	Results    []sarifResult     `json:"results"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string      `json:"id"`
+	ShortDescription     sarifText   `json:"shortDescription"`
+	DefaultConfiguration sarifConfig `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifText         `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// writeSARIF writes the page's problems as a SARIF 2.1.0 log. Every result is located
+// at the analyzed page and fingerprinted by rule and subject, so dashboards track the
+// same problem across runs.
+func writeSARIF(w io.Writer, r Report) error {
+	a := r.Analysis
+	results := []sarifResult{}
+	add := func(ruleID, level, subject, message string) {
+		sum := sha256.Sum256([]byte(ruleID + "\n" + r.URL + "\n" + subject))
+		results = append(results, sarifResult{
+			RuleID:              ruleID,
+			Level:               level,
+			Message:             sarifText{message},
+			Locations:           []sarifLocation{{sarifPhysicalLocation{sarifArtifactLocation{r.URL}}}},
+			PartialFingerprints: map[string]string{fingerprintKey: hex.EncodeToString(sum[:16])},
+		})
+	}
+
+	for _, l := range r.links() {
+		switch l.State {
+		case LinkBroken:
+			add(RuleBrokenLink, levelError, l.URL, fmt.Sprintf("Broken link %s: %s", l.URL, l.Status))
+		case LinkExpiredCertificate:
+			add(RuleLinkCertificate, levelError, l.URL, fmt.Sprintf("Link %s: %s", l.URL, l.Status))
+		}
+	}
+
+	if tls := a.TLS; tls != nil {
+		var problems []string
+		if tls.Expired {
+			problems = append(problems, "is expired")
+		}
+		if tls.HostnameMismatch {
+			problems = append(problems, "does not match the host name")
+		}
+		if tls.SelfSigned {
+			problems = append(problems, "is self-signed")
+		}
+		if len(problems) > 0 {
+			add(RulePageCertificate, levelError, "tls", "The page's TLS certificate "+strings.Join(problems, " and "))
+		}
+	}
+
+	for _, item := range a.MixedContent.Items {
+		level := levelWarning
+		if item.Type == models.MixedContentActive {
+			level = levelError
+		}
+		add(RuleMixedContent, level, item.Type+" "+item.URL,
+			fmt.Sprintf("Insecure %s content: <%s %s=%q>", item.Type, item.Element, item.Attribute, item.URL))
+	}
+
+	if a.Truncated {
+		add(RuleAnalysisTruncated, levelNote, a.TruncatedReason, "The analysis is partial: "+a.TruncatedReason)
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: toolName, Rules: sarifRules}},
+		Results: results,
+	}
+	if r.ResultID != "" {
+		run.Properties = map[string]string{"result_id": r.ResultID}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}})
+}
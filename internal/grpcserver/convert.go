@@ -0,0 +1,131 @@
+package grpcserver
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/export"
+	"web-analyzer/internal/models"
+	"web-analyzer/pkg/analyzerpb"
+)
+
+func requestFromProto(req *analyzerpb.AnalyzeRequest) models.AnalyzeRequest {
+	r := models.AnalyzeRequest{
+		URL:              req.GetUrl(),
+		FlagExpiredCerts: req.GetFlagExpiredCerts(),
+		CheckLinks:       req.CheckLinks,
+		Sections:         req.GetSections(),
+		LinkConcurrency:  int(req.GetLinkConcurrency()),
+		MaxLinks:         int(req.GetMaxLinks()),
+		Include:          req.GetInclude(),
+		Exclude:          req.GetExclude(),
+		UserAgent:        req.GetUserAgent(),
+		AnyStatus:        req.GetAnyStatus(),
+	}
+	if timeout := req.GetLinkTimeout(); timeout != nil {
+		r.LinkTimeout = timeout.AsDuration().String()
+	}
+	return r
+}
+
+var linkStates = map[string]analyzerpb.LinkState{
+	export.LinkOK:                 analyzerpb.LinkState_LINK_STATE_OK,
+	export.LinkBroken:             analyzerpb.LinkState_LINK_STATE_BROKEN,
+	export.LinkExpiredCertificate: analyzerpb.LinkState_LINK_STATE_EXPIRED_CERTIFICATE,
+	export.LinkBlocked:            analyzerpb.LinkState_LINK_STATE_BLOCKED,
+}
+
+func linkToProto(link analysis.LinkResult) *analyzerpb.LinkResult {
+	return &analyzerpb.LinkResult{
+		Url:      link.URL,
+		External: link.External,
+		State:    linkStates[export.LinkState(link.Status)],
+		Status:   link.Status,
+	}
+}
+
+func pageToProto(p *models.PageAnalysis) *analyzerpb.PageAnalysis {
+	headings := make(map[string]int32, len(p.Headings))
+	for tag, n := range p.Headings {
+		headings[tag] = int32(n)
+	}
+	return &analyzerpb.PageAnalysis{
+		ResultId:            p.ResultID,
+		HtmlVersion:         p.HTMLVersion,
+		DocumentMode:        p.DocumentMode,
+		Title:               p.Title,
+		Charset:             p.Charset,
+		Headings:            headings,
+		InternalLinks:       int32(p.InternalLinks),
+		ExternalLinks:       int32(p.ExternalLinks),
+		BrokenLinks:         int32(p.BrokenLinks),
+		BlockedLinks:        int32(p.BlockedLinks),
+		SkippedLinks:        int32(p.SkippedLinks),
+		HasLoginForm:        p.HasLoginForm,
+		PageSizeBytes:       p.PageSize,
+		CompressedSizeBytes: p.CompressedSize,
+		ContentEncoding:     p.ContentEncoding,
+		LoadTimeMs:          p.LoadTime,
+		LinksStatus:         p.LinksStatus,
+		AnalysisDuration:    p.AnalysisDuration,
+		MetaTags:            p.MetaTags,
+		Tls:                 tlsToProto(p.TLS),
+		ExpiredCertLinks:    p.ExpiredCertLinks,
+		MixedContent:        mixedContentToProto(p.MixedContent),
+		Truncated:           p.Truncated,
+		TruncatedReason:     p.TruncatedReason,
+		StatusCode:          int32(p.StatusCode),
+		Headers:             p.Headers,
+		Soft_404:            p.Soft404,
+		Soft_404Reason:      p.Soft404Reason,
+		Cancelled:           p.Cancelled,
+	}
+}
+
+func tlsToProto(t *models.TLSInfo) *analyzerpb.TLSInfo {
+	if t == nil {
+		return nil
+	}
+	certs := make([]*analyzerpb.CertificateInfo, len(t.Certificates))
+	for i, c := range t.Certificates {
+		certs[i] = &analyzerpb.CertificateInfo{
+			Subject:       c.Subject,
+			Issuer:        c.Issuer,
+			Sans:          c.SANs,
+			NotBefore:     timestamppb.New(c.NotBefore),
+			NotAfter:      timestamppb.New(c.NotAfter),
+			DaysRemaining: int32(c.DaysRemaining),
+			IsCa:          c.IsCA,
+		}
+	}
+	return &analyzerpb.TLSInfo{
+		Version:            t.Version,
+		CipherSuite:        t.CipherSuite,
+		NegotiatedProtocol: t.NegotiatedProtocol,
+		ServerName:         t.ServerName,
+		Certificates:       certs,
+		Expired:            t.Expired,
+		DaysRemaining:      int32(t.DaysRemaining),
+		HostnameMismatch:   t.HostnameMismatch,
+		SelfSigned:         t.SelfSigned,
+		VerificationError:  t.VerificationError,
+	}
+}
+
+func mixedContentToProto(m models.MixedContentInfo) *analyzerpb.MixedContentInfo {
+	items := make([]*analyzerpb.MixedContentItem, len(m.Items))
+	for i, item := range m.Items {
+		items[i] = &analyzerpb.MixedContentItem{
+			Element:   item.Element,
+			Attribute: item.Attribute,
+			Url:       item.URL,
+			Type:      item.Type,
+		}
+	}
+	return &analyzerpb.MixedContentInfo{
+		Active:         int32(m.Active),
+		Passive:        int32(m.Passive),
+		DowngradeLinks: int32(m.DowngradeLinks),
+		Items:          items,
+	}
+}
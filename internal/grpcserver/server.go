@@ -0,0 +1,282 @@
+// Package grpcserver serves the analysis API over gRPC, next to the REST routes, with
+// the standard health checking and reflection services.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/ratelimit"
+	"web-analyzer/pkg/analyzerpb"
+	"web-analyzer/pkg/metrics"
+)
+
+const (
+	metadataAPIKey    = "x-api-key"
+	metadataRequestID = "x-request-id"
+
+	// routeGroupGRPC labels gRPC calls in the rate limited requests metric.
+	routeGroupGRPC = "grpc"
+)
+
+// Options configures the gRPC server.
+type Options struct {
+	// Auth, when set, requires the static API key of one of its keys in the x-api-key
+	// metadata or as "authorization: Bearer <key>". Health checks and reflection stay open.
+	Auth *auth.Authenticator
+	// RateLimiter throttles analyzer calls per API key, or per peer address for
+	// unauthenticated calls. Pass the REST API group's limiter to give a client one
+	// budget across both. Nil leaves calls unthrottled.
+	RateLimiter ratelimit.Limiter
+	// MaxBatchSize bounds the requests of one BatchAnalyze call, analysis.MaxBatchSize when zero.
+	MaxBatchSize int
+	// BatchConcurrency bounds the pages of one batch analyzed at once,
+	// analysis.BatchConcurrency when zero.
+	BatchConcurrency int
+}
+
+// Server is the gRPC server with the analyzer, health and reflection services registered.
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+func New(opts Options) *Server {
+	if opts.MaxBatchSize <= 0 {
+		opts.MaxBatchSize = analysis.MaxBatchSize
+	}
+	if opts.BatchConcurrency <= 0 {
+		opts.BatchConcurrency = analysis.BatchConcurrency
+	}
+
+	i := interceptors{auth: opts.Auth, limiter: opts.RateLimiter, maxBatchSize: opts.MaxBatchSize}
+	s := &Server{
+		grpc: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(i.unary),
+			grpc.ChainStreamInterceptor(i.stream),
+		),
+		health: health.NewServer(),
+	}
+
+	analyzerpb.RegisterAnalyzerServiceServer(s.grpc, &analyzerService{opts: opts})
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+	s.health.SetServingStatus(analyzerpb.AnalyzerService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	return s
+}
+
+// Serve accepts connections on lis until Shutdown.
+func (s *Server) Serve(lis net.Listener) error {
+	slog.Info("starting gRPC server", "address", lis.Addr().String())
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown reports NOT_SERVING to health checks and waits for in-flight calls to finish
+// until ctx is done, then closes the remaining ones.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Error("gRPC server forced to shutdown", "error", ctx.Err())
+		s.grpc.Stop()
+	}
+}
+
+type (
+	requestIDKey struct{}
+	apiKeyIDKey  struct{}
+)
+
+// requestID returns the ID the interceptors assigned to the call.
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// interceptors authenticate, rate limit, log and count calls, charge their analyses
+// against the key's daily quota, and tag them with a request ID taken from the
+// x-request-id metadata or generated, like the REST middleware does.
+type interceptors struct {
+	auth         *auth.Authenticator
+	limiter      ratelimit.Limiter
+	maxBatchSize int
+}
+
+func (i interceptors) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	var resp any
+	ctx, err := i.begin(ctx, info.FullMethod)
+	if err == nil {
+		err = i.chargeQuota(ctx, req)
+	}
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+	i.end(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func (i interceptors) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, err := i.begin(ss.Context(), info.FullMethod)
+	if err == nil {
+		// AnalyzeStream, the only streaming method, analyzes one page.
+		err = i.chargeQuota(ctx, nil)
+	}
+	if err == nil {
+		err = handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+	i.end(ctx, info.FullMethod, start, err)
+	return err
+}
+
+func (i interceptors) begin(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := first(md, metadataRequestID)
+	if id == "" {
+		id = uuid.New().String()
+	}
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, id))
+
+	if !strings.HasPrefix(method, "/"+analyzerpb.AnalyzerService_ServiceDesc.ServiceName+"/") {
+		return ctx, nil
+	}
+	if i.auth != nil {
+		var err error
+		if ctx, err = i.authenticate(ctx, md); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, i.rateLimit(ctx)
+}
+
+// authenticate checks the presented API key, applies its own rate limit and stores its
+// ID in the context.
+func (i interceptors) authenticate(ctx context.Context, md metadata.MD) (context.Context, error) {
+	presented := first(md, metadataAPIKey)
+	if presented == "" {
+		if bearer, ok := strings.CutPrefix(first(md, "authorization"), "Bearer "); ok {
+			presented = strings.TrimSpace(bearer)
+		}
+	}
+	keyID, err := i.auth.AuthenticateAPIKey(ctx, presented)
+	var limitErr *auth.LimitError
+	if errors.As(err, &limitErr) {
+		metrics.APIKeyRequests.WithLabelValues(keyID, "rate_limited").Inc()
+		return ctx, limitStatus(limitErr).Err()
+	}
+	if err != nil {
+		metrics.APIKeyRequests.WithLabelValues("anonymous", "unauthorized").Inc()
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	metrics.APIKeyRequests.WithLabelValues(keyID, "allowed").Inc()
+	return context.WithValue(ctx, apiKeyIDKey{}, keyID), nil
+}
+
+// rateLimit applies the shared client rate limiter, keyed like the REST middleware keys
+// it. Limiter errors fail open.
+func (i interceptors) rateLimit(ctx context.Context) error {
+	if i.limiter == nil {
+		return nil
+	}
+
+	keyID, _ := ctx.Value(apiKeyIDKey{}).(string)
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+	}
+	key := ratelimit.ClientKey(keyID, addr)
+
+	result, err := i.limiter.Allow(ctx, key)
+	if err != nil {
+		slog.Warn("rate limiter unavailable, allowing request", "key", key, "error", err)
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+	metrics.RateLimited.WithLabelValues(routeGroupGRPC).Inc()
+	return limitStatus(&auth.LimitError{
+		Code:       models.CodeRateLimited,
+		Message:    "rate limit exceeded",
+		RetryAfter: result.RetryAfter,
+	}).Err()
+}
+
+// chargeQuota counts the analyses req asks for against the authenticated key's daily
+// quota: one per page of a batch, one otherwise. Batches of a size BatchAnalyze rejects
+// are rejected here, before anything is counted.
+func (i interceptors) chargeQuota(ctx context.Context, req any) error {
+	keyID, ok := ctx.Value(apiKeyIDKey{}).(string)
+	if !ok {
+		return nil
+	}
+
+	n := 1
+	if batch, ok := req.(*analyzerpb.BatchAnalyzeRequest); ok {
+		n = len(batch.GetRequests())
+		if st := batchSizeStatus(n, i.maxBatchSize); st != nil {
+			return st.Err()
+		}
+	}
+
+	var limitErr *auth.LimitError
+	if _, err := i.auth.ChargeQuota(keyID, n); errors.As(err, &limitErr) {
+		return limitStatus(limitErr).Err()
+	}
+	return nil
+}
+
+func (i interceptors) end(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	metrics.GRPCRequests.WithLabelValues(method, code.String()).Inc()
+	slog.Info("gRPC request processed", "method", method, "code", code.String(),
+		"latency", time.Since(start), "requestID", requestID(ctx))
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream is a ServerStream carrying the context set up by the interceptor.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
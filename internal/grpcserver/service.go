@@ -0,0 +1,174 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/auth"
+	"web-analyzer/pkg/analyzerpb"
+)
+
+type analyzerService struct {
+	analyzerpb.UnimplementedAnalyzerServiceServer
+	opts Options
+}
+
+func (s *analyzerService) Analyze(ctx context.Context, req *analyzerpb.AnalyzeRequest) (*analyzerpb.AnalyzeResponse, error) {
+	result, err := analysis.Analyze(ctx, requestFromProto(req), requestID(ctx))
+	if err != nil {
+		logFailure(ctx, req.GetUrl(), err)
+		return nil, statusError(err).Err()
+	}
+	return &analyzerpb.AnalyzeResponse{Analysis: pageToProto(result)}, nil
+}
+
+func (s *analyzerService) AnalyzeStream(req *analyzerpb.AnalyzeRequest, stream analyzerpb.AnalyzerService_AnalyzeStreamServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// onLink is called from the analysis goroutine only, and never after AnalyzeStream
+	// returned, so sends need no locking.
+	var sendErr error
+	send := func(msg *analyzerpb.AnalyzeStreamResponse) {
+		if sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(msg); sendErr != nil {
+			cancel()
+		}
+	}
+
+	result, err := analysis.AnalyzeStream(ctx, requestFromProto(req), requestID(ctx), func(link analysis.LinkResult) {
+		send(&analyzerpb.AnalyzeStreamResponse{Event: &analyzerpb.AnalyzeStreamResponse_Link{Link: linkToProto(link)}})
+	})
+
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		logFailure(ctx, req.GetUrl(), err)
+		return statusError(err).Err()
+	}
+	return stream.Send(&analyzerpb.AnalyzeStreamResponse{Event: &analyzerpb.AnalyzeStreamResponse_Analysis{Analysis: pageToProto(result)}})
+}
+
+func (s *analyzerService) BatchAnalyze(ctx context.Context, req *analyzerpb.BatchAnalyzeRequest) (*analyzerpb.BatchAnalyzeResponse, error) {
+	requests := req.GetRequests()
+	if st := batchSizeStatus(len(requests), s.opts.MaxBatchSize); st != nil {
+		return nil, st.Err()
+	}
+
+	results := make([]*analyzerpb.BatchResult, len(requests))
+	sem := make(chan struct{}, s.opts.BatchConcurrency)
+	var wg sync.WaitGroup
+	for i, r := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = &analyzerpb.BatchResult{Url: r.GetUrl()}
+			result, err := analysis.Analyze(ctx, requestFromProto(r), requestID(ctx))
+			if err != nil {
+				logFailure(ctx, r.GetUrl(), err)
+				results[i].Outcome = &analyzerpb.BatchResult_Error{Error: statusError(err).Proto()}
+				return
+			}
+			results[i].Outcome = &analyzerpb.BatchResult_Analysis{Analysis: pageToProto(result)}
+		}()
+	}
+	wg.Wait()
+
+	return &analyzerpb.BatchAnalyzeResponse{Results: results}, nil
+}
+
+// errorDomain is the ErrorInfo domain of the statuses returned for failed analyses.
+const errorDomain = "web-analyzer"
+
+// statusError maps an analysis error to a gRPC status, following the REST status codes.
+// Statuses other than invalid requests carry an ErrorInfo whose reason is the REST error
+// code.
+func statusError(err error) *status.Status {
+	var reqErr *analysis.RequestError
+	switch {
+	case errors.As(err, &reqErr):
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(reqErr.Fields))
+		for i, f := range reqErr.Fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message}
+		}
+		return badRequest("Invalid analysis options", violations...)
+	}
+
+	_, resp := analysis.ResponseFor(err)
+	code := codes.Internal
+	switch {
+	case errors.Is(err, analysis.ErrInvalidInput):
+		code = codes.InvalidArgument
+	case errors.Is(err, analysis.ErrBlocked):
+		code = codes.PermissionDenied
+	case errors.Is(err, analysis.ErrTooLarge):
+		code = codes.ResourceExhausted
+	case errors.Is(err, analysis.ErrUpstreamUnreachable),
+		errors.Is(err, analysis.ErrUpstreamStatus),
+		errors.Is(err, analysis.ErrUpstreamInvalid):
+		code = codes.FailedPrecondition
+	case errors.Is(err, analysis.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	st := status.New(code, err.Error())
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: resp.Code, Domain: errorDomain}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// batchSizeStatus rejects batches of n requests unless 1 <= n <= maxSize.
+func batchSizeStatus(n, maxSize int) *status.Status {
+	if n >= 1 && n <= maxSize {
+		return nil
+	}
+	return badRequest(fmt.Sprintf("a batch must hold between 1 and %d requests", maxSize),
+		&errdetails.BadRequest_FieldViolation{
+			Field:       "requests",
+			Description: fmt.Sprintf("must hold between 1 and %d requests, got %d", maxSize, n),
+		})
+}
+
+// limitStatus maps a rate limit or quota rejection to RESOURCE_EXHAUSTED with the REST
+// error code as the ErrorInfo reason and, for rate limits, a RetryInfo.
+func limitStatus(err *auth.LimitError) *status.Status {
+	st := status.New(codes.ResourceExhausted, err.Error())
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: err.Code, Domain: errorDomain}}
+	if err.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(err.RetryAfter)})
+	}
+	if detailed, err := st.WithDetails(details...); err == nil {
+		return detailed
+	}
+	return st
+}
+
+func badRequest(msg string, violations ...*errdetails.BadRequest_FieldViolation) *status.Status {
+	st := status.New(codes.InvalidArgument, msg)
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+func logFailure(ctx context.Context, url string, err error) {
+	slog.Error("analysis failed..", "handler", "grpc", "requestID", requestID(ctx), "url", url, "error", err)
+}
@@ -1,21 +1,56 @@
 package models
 
-import "sync"
-
-type PageAnalysis struct {
-	HTMLVersion      string            `json:"html_version"`
-	Title            string            `json:"title"`
-	Headings         map[string]int    `json:"headings"`
-	InternalLinks    int               `json:"internal_links"`
-	ExternalLinks    int               `json:"external_links"`
-	BrokenLinks      int               `json:"broken_links"`
-	HasLoginForm     bool              `json:"has_login_form"`
-	PageSize         int64             `json:"page_size_bytes,omitempty"`
-	LoadTime         int64             `json:"load_time_ms,omitempty"`
-	LinksStatus      map[string]string `json:"links_status,omitempty"`
-	AnalysisDuration string            `json:"analysis_duration,omitempty"`
-	MetaTags         map[string]string `json:"meta_tags,omitempty"`
-	Mutex            sync.Mutex
+import (
+	"slices"
+	"time"
+)
+
+// AnalysisOptions tunes a single page analysis. The zero value keeps the default behaviour.
+type AnalysisOptions struct {
+	FlagExpiredCerts bool // report links whose TLS certificate has expired in ExpiredCertLinks
+	Limits           ParseLimits
+	Sections         Sections      // report sections to compute, all of them when empty
+	SkipLinkCheck    bool          // count links without requesting them
+	LinkTimeout      time.Duration // per link check, the configured default when zero
+	LinkConcurrency  int           // concurrent link checks, the configured default when zero
+	IncludeLinks     []string      // when set, only links matching one of these regular expressions are checked
+	ExcludeLinks     []string      // links matching one of these regular expressions are not checked
+	UserAgent        string        // sent with the page and link requests, the configured default when empty
+	AnyStatus        bool          // analyze HTML pages answered with any status instead of failing with ErrUpstreamStatus
+}
+
+// Report sections that can be selected with AnalysisOptions.Sections.
+const (
+	SectionHTMLVersion  = "html_version" // html_version and document_mode
+	SectionTitle        = "title"
+	SectionHeadings     = "headings"
+	SectionLinks        = "links" // link counts and link checks
+	SectionLoginForm    = "login_form"
+	SectionMetaTags     = "meta_tags"
+	SectionTLS          = "tls"
+	SectionMixedContent = "mixed_content"
+)
+
+// AllSections lists every report section.
+var AllSections = Sections{
+	SectionHTMLVersion, SectionTitle, SectionHeadings, SectionLinks,
+	SectionLoginForm, SectionMetaTags, SectionTLS, SectionMixedContent,
+}
+
+// Sections is a set of report section names. An empty set selects every section.
+type Sections []string
+
+func (s Sections) Has(section string) bool {
+	return len(s) == 0 || slices.Contains(s, section)
+}
+
+// ParseLimits bounds the resources spent on one page. Zero fields fall back to the
+// analyzer defaults and negative values disable the limit.
+type ParseLimits struct {
+	MaxBodyBytes int64 // decoded body bytes read before parsing stops
+	MaxNodes     int   // element nodes parsed before parsing stops
+	MaxLinks     int   // links extracted and checked
+	MaxDepth     int   // element nesting depth parsed before parsing stops
 }
 
 type LinkInfo struct {
@@ -23,8 +58,3 @@ type LinkInfo struct {
 	IsExternal bool
 	BaseURL    string
 }
-
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
-}
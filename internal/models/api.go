@@ -0,0 +1,50 @@
+package models
+
+import "web-analyzer/pkg/api"
+
+// The request and response types of the HTTP API are defined in pkg/api, so that
+// clients can use them without importing the server.
+type (
+	PageAnalysis         = api.PageAnalysis
+	MixedContentInfo     = api.MixedContentInfo
+	MixedContentItem     = api.MixedContentItem
+	TLSInfo              = api.TLSInfo
+	CertificateInfo      = api.CertificateInfo
+	AnalysisRecord       = api.AnalysisRecord
+	HistoryResponse      = api.HistoryResponse
+	AnalyzeRequest       = api.AnalyzeRequest
+	BatchAnalyzeRequest  = api.BatchAnalyzeRequest
+	BatchAnalyzeResponse = api.BatchAnalyzeResponse
+	BatchResult          = api.BatchResult
+	CallbackAccepted     = api.CallbackAccepted
+	CallbackPayload      = api.CallbackPayload
+	ErrorResponse        = api.ErrorResponse
+	Problem              = api.Problem
+	FieldError           = api.FieldError
+)
+
+const (
+	MixedContentActive        = api.MixedContentActive
+	MixedContentPassive       = api.MixedContentPassive
+	MixedContentLinkDowngrade = api.MixedContentLinkDowngrade
+	CodeInvalidInput          = api.CodeInvalidInput
+	CodeUpstreamUnreachable   = api.CodeUpstreamUnreachable
+	CodeUpstreamStatus        = api.CodeUpstreamStatus
+	CodeUpstreamInvalid       = api.CodeUpstreamInvalid
+	CodeTimeout               = api.CodeTimeout
+	CodeTooLarge              = api.CodeTooLarge
+	CodeBlocked               = api.CodeBlocked
+	CodeCancelled             = api.CodeCancelled
+	CodeUnauthorized          = api.CodeUnauthorized
+	CodeNotFound              = api.CodeNotFound
+	CodeConflict              = api.CodeConflict
+	CodeRateLimited           = api.CodeRateLimited
+	CodeQuotaExceeded         = api.CodeQuotaExceeded
+	CodeUnavailable           = api.CodeUnavailable
+	CodeNotImplemented        = api.CodeNotImplemented
+	CodeInternal              = api.CodeInternal
+	ProblemTypePrefix         = api.ProblemTypePrefix
+)
+
+// ErrorCodes lists every ErrorResponse code.
+var ErrorCodes = api.ErrorCodes
@@ -0,0 +1,156 @@
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlocked is returned (wrapped in a *BlockedError) when a connection targets a forbidden address.
+var ErrBlocked = errors.New("destination blocked by network policy")
+
+// BlockedError records which address a connection attempt was refused for.
+type BlockedError struct {
+	Addr   netip.Addr
+	Reason string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s: %s is %s", ErrBlocked, e.Addr, e.Reason)
+}
+
+func (e *BlockedError) Unwrap() error {
+	return ErrBlocked
+}
+
+// defaultBlocked lists the ranges an outbound fetch must never reach unless explicitly allowed:
+// loopback, private, link-local (including cloud metadata endpoints), CGNAT and reserved space.
+var defaultBlocked = []struct {
+	prefix string
+	reason string
+}{
+	{"0.0.0.0/8", "an unspecified address"},
+	{"10.0.0.0/8", "a private address"},
+	{"100.64.0.0/10", "a shared address space (CGNAT) address"},
+	{"127.0.0.0/8", "a loopback address"},
+	{"169.254.0.0/16", "a link-local address"},
+	{"172.16.0.0/12", "a private address"},
+	{"192.0.0.0/24", "an IETF protocol assignment address"},
+	{"192.168.0.0/16", "a private address"},
+	{"198.18.0.0/15", "a benchmarking address"},
+	{"224.0.0.0/4", "a multicast address"},
+	{"240.0.0.0/4", "a reserved address"},
+	{"::/128", "an unspecified address"},
+	{"::1/128", "a loopback address"},
+	{"64:ff9b::/96", "a NAT64 address"},
+	{"fc00::/7", "a unique local address"},
+	{"fe80::/10", "a link-local address"},
+	{"ff00::/8", "a multicast address"},
+}
+
+type rule struct {
+	prefix netip.Prefix
+	reason string
+}
+
+// Guard decides which IP addresses outbound HTTP requests may connect to. Checks happen
+// on the resolved address at dial time, so redirects and DNS rebinding cannot bypass them.
+type Guard struct {
+	allow   []netip.Prefix
+	deny    []netip.Prefix
+	blocked []rule
+}
+
+// New builds a Guard that blocks the default internal ranges. Addresses in allow are
+// permitted even when they fall in a blocked range; addresses in deny are always refused.
+func New(allow, deny []string) (*Guard, error) {
+	g := &Guard{}
+
+	var err error
+	if g.allow, err = parsePrefixes(allow); err != nil {
+		return nil, fmt.Errorf("invalid allow list: %w", err)
+	}
+	if g.deny, err = parsePrefixes(deny); err != nil {
+		return nil, fmt.Errorf("invalid deny list: %w", err)
+	}
+
+	for _, b := range defaultBlocked {
+		g.blocked = append(g.blocked, rule{prefix: netip.MustParsePrefix(b.prefix), reason: b.reason})
+	}
+
+	return g, nil
+}
+
+// Default returns a Guard with only the built-in block list.
+func Default() *Guard {
+	g, _ := New(nil, nil)
+	return g
+}
+
+// Check reports whether a connection to addr is permitted.
+func (g *Guard) Check(addr netip.Addr) error {
+	addr = addr.Unmap()
+
+	for _, p := range g.deny {
+		if p.Contains(addr) {
+			return &BlockedError{Addr: addr, Reason: "in the deny list"}
+		}
+	}
+	for _, p := range g.allow {
+		if p.Contains(addr) {
+			return nil
+		}
+	}
+	for _, r := range g.blocked {
+		if r.prefix.Contains(addr) {
+			return &BlockedError{Addr: addr, Reason: r.reason}
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control hook that refuses connections to blocked addresses.
+func (g *Guard) Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: cannot parse dial address %q", ErrBlocked, address)
+	}
+	return g.Check(addrPort.Addr())
+}
+
+// Dialer returns a net.Dialer that enforces the guard on every connection it makes.
+func (g *Guard) Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   g.Control,
+	}
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,147 @@
+// Package ratelimit throttles clients with token buckets. It is shared by the REST and
+// gRPC servers so a client has one budget across both.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Policy is a token bucket refilled at Rate tokens per second holding up to Burst tokens.
+type Policy struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of one Limiter.Allow call.
+type Result struct {
+	Allowed    bool
+	Limit      int           // bucket capacity
+	Remaining  int           // tokens left after this request
+	RetryAfter time.Duration // until the next token, when not allowed
+	Reset      time.Duration // until the bucket is full again
+}
+
+// Limiter decides whether a client identified by key may make another request.
+// The in-memory implementation is per process; a shared store can implement the same
+// interface to enforce limits across replicas.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// Memory keeps one token bucket per key in process memory.
+type Memory struct {
+	policy    Policy
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// ClientKey is the bucket key of a client: its API key ID when it authenticated, its
+// address otherwise.
+func ClientKey(keyID, addr string) string {
+	if keyID != "" {
+		return "key:" + keyID
+	}
+	return "ip:" + addr
+}
+
+// idleBucketTTL is how long a full, unused bucket is kept before it is evicted.
+const idleBucketTTL = 10 * time.Minute
+
+func NewMemory(policy Policy) *Memory {
+	if policy.Burst <= 0 {
+		policy.Burst = int(math.Ceil(policy.Rate))
+	}
+	return &Memory{
+		policy:  policy,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (l *Memory) Allow(_ context.Context, key string) (Result, error) {
+	now := l.now()
+
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) > idleBucketTTL {
+		l.sweep(now)
+	}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(l.policy.Rate, l.policy.Burst)
+		l.buckets[key] = bucket
+	}
+	l.mu.Unlock()
+
+	return bucket.take(now), nil
+}
+
+// sweep drops buckets of clients that have gone quiet.
+func (l *Memory) sweep(now time.Time) {
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if bucket.isIdle(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// tokenBucket allows rate requests per second with bursts of up to burst requests.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// take consumes a token if one is available.
+func (b *tokenBucket) take(now time.Time) Result {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	result := Result{Limit: int(b.burst)}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = b.secondsFor(1 - b.tokens)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = b.secondsFor(b.burst - b.tokens)
+	return result
+}
+
+func (b *tokenBucket) secondsFor(tokens float64) time.Duration {
+	return time.Duration(tokens / b.rate * float64(time.Second))
+}
+
+// isIdle reports whether the bucket has been unused for idleBucketTTL and has refilled,
+// so dropping it does not hand a client any tokens it would not already have.
+func (b *tokenBucket) isIdle(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	idle := now.Sub(b.last)
+	return !b.last.IsZero() && idle > idleBucketTTL && b.tokens+idle.Seconds()*b.rate >= b.burst
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"web-analyzer/internal/diff"
+	"web-analyzer/internal/models"
+	"web-analyzer/pkg/api"
+)
+
+// Rule types, defined in pkg/api with the rest of the schedule types.
+const (
+	RuleThreshold            = api.RuleThreshold
+	RuleTitleMissing         = api.RuleTitleMissing
+	RuleTitleChanged         = api.RuleTitleChanged
+	RuleHTMLVersionChanged   = api.RuleHTMLVersionChanged
+	RuleLoginFormAppeared    = api.RuleLoginFormAppeared
+	RuleLoginFormDisappeared = api.RuleLoginFormDisappeared
+	RuleNewBrokenLinks       = api.RuleNewBrokenLinks
+	RuleAnalysisFailed       = api.RuleAnalysisFailed
+)
+
+// thresholdFields are the result fields threshold rules can test. A field reports false
+// when the result has no value for it, and its rules are skipped for that run.
+var thresholdFields = map[string]func(*models.PageAnalysis) (float64, bool){
+	"broken_links":          func(p *models.PageAnalysis) (float64, bool) { return float64(p.BrokenLinks), true },
+	"blocked_links":         func(p *models.PageAnalysis) (float64, bool) { return float64(p.BlockedLinks), true },
+	"internal_links":        func(p *models.PageAnalysis) (float64, bool) { return float64(p.InternalLinks), true },
+	"external_links":        func(p *models.PageAnalysis) (float64, bool) { return float64(p.ExternalLinks), true },
+	"mixed_content_active":  func(p *models.PageAnalysis) (float64, bool) { return float64(p.MixedContent.Active), true },
+	"mixed_content_passive": func(p *models.PageAnalysis) (float64, bool) { return float64(p.MixedContent.Passive), true },
+	"page_size_bytes":       func(p *models.PageAnalysis) (float64, bool) { return float64(p.PageSize), true },
+	"load_time_ms":          func(p *models.PageAnalysis) (float64, bool) { return float64(p.LoadTime), true },
+	"tls_days_remaining": func(p *models.PageAnalysis) (float64, bool) {
+		// http:// pages, and runs without the tls section, have no certificate.
+		if p.TLS == nil {
+			return 0, false
+		}
+		return float64(p.TLS.DaysRemaining), true
+	},
+}
+
+var thresholdOps = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+func validateRule(r Rule) error {
+	switch r.Type {
+	case RuleThreshold:
+		if _, ok := thresholdFields[r.Field]; !ok {
+			return fmt.Errorf("unknown threshold field %q", r.Field)
+		}
+		if _, ok := thresholdOps[r.Op]; !ok {
+			return fmt.Errorf("unknown operator %q", r.Op)
+		}
+	case RuleTitleMissing, RuleTitleChanged, RuleHTMLVersionChanged, RuleLoginFormAppeared,
+		RuleLoginFormDisappeared, RuleNewBrokenLinks, RuleAnalysisFailed:
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
+
+// evaluate returns the alerts raised by a run. current is nil when the analysis failed
+// with runErr; previous is nil for a job's first successful run.
+func evaluate(rules []Rule, previous, current *models.AnalysisRecord, runErr error) []Alert {
+	var alerts []Alert
+	raise := func(r Rule, format string, args ...any) {
+		alerts = append(alerts, Alert{Rule: r.String(), Message: fmt.Sprintf(format, args...)})
+	}
+
+	if current == nil {
+		for _, r := range rules {
+			if r.Type == RuleAnalysisFailed {
+				raise(r, "analysis failed: %v", runErr)
+			}
+		}
+		return alerts
+	}
+
+	page := current.Analysis
+	var changes *diff.Report
+	if previous != nil {
+		changes = diff.Compare(previous, current)
+	}
+
+	for _, r := range rules {
+		switch r.Type {
+		case RuleThreshold:
+			if v, ok := thresholdFields[r.Field](page); ok && thresholdOps[r.Op](v, r.Value) {
+				raise(r, "%s is %s", r.Field, strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		case RuleTitleMissing:
+			if strings.TrimSpace(page.Title) == "" {
+				raise(r, "the page has no title")
+			}
+		case RuleTitleChanged:
+			if changes != nil && changes.Title != nil {
+				raise(r, "title changed from %q to %q", changes.Title.From, changes.Title.To)
+			}
+		case RuleHTMLVersionChanged:
+			if changes != nil && changes.HTMLVersion != nil {
+				raise(r, "HTML version changed from %s to %s", changes.HTMLVersion.From, changes.HTMLVersion.To)
+			}
+		case RuleLoginFormAppeared:
+			if changes != nil && changes.LoginForm != nil && changes.LoginForm.Appeared {
+				raise(r, "a login form appeared")
+			}
+		case RuleLoginFormDisappeared:
+			if changes != nil && changes.LoginForm != nil && changes.LoginForm.Disappeared {
+				raise(r, "the login form disappeared")
+			}
+		case RuleNewBrokenLinks:
+			if changes != nil && len(changes.Links.NowBroken) > 0 {
+				urls := make([]string, 0, len(changes.Links.NowBroken))
+				for _, link := range changes.Links.NowBroken {
+					urls = append(urls, link.URL)
+				}
+				raise(r, "%d link(s) broke: %s", len(urls), strings.Join(urls, ", "))
+			}
+		}
+	}
+	return alerts
+}
@@ -0,0 +1,568 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/storage"
+	"web-analyzer/internal/webhook"
+	"web-analyzer/pkg/api"
+	"web-analyzer/pkg/metrics"
+)
+
+// EventAlert is the webhook event sent when a scheduled run raises alerts.
+const EventAlert = api.EventAlert
+
+// MinInterval is the shortest interval a job can run at.
+const MinInterval = time.Minute
+
+var (
+	ErrJobNotFound = errors.New("schedule not found")
+	ErrJobRunning  = errors.New("schedule is already running")
+)
+
+// ValidationError lists the invalid fields of a JobSpec.
+type ValidationError struct {
+	Fields []models.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return "invalid schedule: " + strings.Join(msgs, "; ")
+}
+
+// The schedule types are defined in pkg/api, which clients import without the server.
+type (
+	JobSpec      = api.JobSpec
+	Job          = api.Job
+	Run          = api.Run
+	Rule         = api.Rule
+	Alert        = api.Alert
+	AlertPayload = api.AlertPayload
+)
+
+// Options configures a Scheduler.
+type Options struct {
+	// Analyze runs one analysis. Required.
+	Analyze func(ctx context.Context, url string) (*models.PageAnalysis, error)
+	// Store keeps the results of scheduled runs. Optional.
+	Store storage.Store
+	// Sender delivers alert webhooks. Jobs with a webhook URL are rejected without it.
+	Sender *webhook.Sender
+	// JobsPath is the file jobs are persisted to. Jobs are kept in memory only when empty.
+	JobsPath string
+	// MaxConcurrent bounds the number of jobs running at once, 1 when zero.
+	MaxConcurrent int
+	// RunTimeout bounds a single analysis, 2 minutes when zero.
+	RunTimeout time.Duration
+	// ChargeQuota counts n analyses against the daily quota of keyID, the key that
+	// created a job, before each of its runs. A run it refuses is recorded as failed
+	// without analyzing the page, and RunNow returns its error. Optional.
+	ChargeQuota func(keyID string, n int) error
+}
+
+// Scheduler runs registered jobs on their schedules and raises alerts on their results.
+type Scheduler struct {
+	opts Options
+	sem  chan struct{}
+	wake chan struct{}
+	wg   sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+type job struct {
+	Job
+	schedule schedule
+	previous *models.AnalysisRecord
+	running  bool
+}
+
+// New creates a scheduler and loads the jobs persisted at opts.JobsPath.
+func New(opts Options) (*Scheduler, error) {
+	if opts.Analyze == nil {
+		return nil, errors.New("scheduler: Analyze is required")
+	}
+	if opts.MaxConcurrent < 1 {
+		opts.MaxConcurrent = 1
+	}
+	if opts.RunTimeout <= 0 {
+		opts.RunTimeout = 2 * time.Minute
+	}
+
+	s := &Scheduler{
+		opts: opts,
+		sem:  make(chan struct{}, opts.MaxConcurrent),
+		wake: make(chan struct{}, 1),
+		jobs: make(map[string]*job),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add validates spec and registers it. Its first run is the next scheduled time.
+func (s *Scheduler) Add(spec JobSpec, keyID string) (*Job, error) {
+	sched, err := s.validate(&spec)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	j := &job{
+		Job: Job{
+			ID:        uuid.New().String(),
+			JobSpec:   spec,
+			CreatedAt: now,
+			NextRun:   sched.Next(now).UTC(),
+			KeyID:     keyID,
+		},
+		schedule: sched,
+	}
+
+	s.mu.Lock()
+	s.jobs[j.ID] = j
+	err = s.persistLocked()
+	if err != nil {
+		delete(s.jobs, j.ID)
+	}
+	result := j.Job
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	s.notify()
+	return &result, nil
+}
+
+// Get returns the job with the given id.
+func (s *Scheduler) Get(id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	result := j.Job
+	return &result, nil
+}
+
+// List returns every job, oldest first.
+func (s *Scheduler) List() []Job {
+	s.mu.Lock()
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j.Job)
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(jobs, func(a, b Job) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return jobs
+}
+
+// Remove unregisters a job. A run in progress finishes but is not recorded.
+func (s *Scheduler) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return ErrJobNotFound
+	}
+	delete(s.jobs, id)
+	if err := s.persistLocked(); err != nil {
+		s.jobs[id] = j
+		return err
+	}
+	return nil
+}
+
+// RunNow runs a job immediately, outside its schedule, and returns the outcome.
+func (s *Scheduler) RunNow(ctx context.Context, id string) (*Run, error) {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrJobNotFound
+	}
+	if j.running {
+		s.mu.Unlock()
+		return nil, ErrJobRunning
+	}
+	j.running = true
+	s.mu.Unlock()
+
+	if err := s.charge(j); err != nil {
+		s.mu.Lock()
+		j.running = false
+		s.mu.Unlock()
+		return nil, err
+	}
+	return s.run(ctx, j), nil
+}
+
+// Run starts due jobs until ctx is cancelled, then waits for running jobs to finish.
+// Runs missed while the scheduler was stopped are coalesced into one.
+func (s *Scheduler) Run(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		next := s.startDue(ctx, time.Now())
+
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = min(time.Until(next), wait)
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-timer.C:
+		case <-s.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// startDue starts the jobs due at now and returns the earliest upcoming run time.
+func (s *Scheduler) startDue(ctx context.Context, now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next time.Time
+	for _, j := range s.jobs {
+		if !j.running && !j.NextRun.IsZero() && !j.NextRun.After(now) {
+			j.running = true
+			j.NextRun = j.schedule.Next(now).UTC()
+
+			s.wg.Add(1)
+			go func(j *job) {
+				defer s.wg.Done()
+				if err := s.charge(j); err != nil {
+					s.refuse(j, err)
+					return
+				}
+				s.run(ctx, j)
+			}(j)
+		}
+		if j.NextRun.IsZero() || j.running {
+			continue
+		}
+		if next.IsZero() || j.NextRun.Before(next) {
+			next = j.NextRun
+		}
+	}
+	return next
+}
+
+// run executes j, which the caller has marked as running.
+func (s *Scheduler) run(ctx context.Context, j *job) *Run {
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		s.mu.Lock()
+		j.running = false
+		s.mu.Unlock()
+		return &Run{StartedAt: time.Now().UTC(), Duration: "0s", Error: ctx.Err().Error()}
+	}
+
+	start := time.Now()
+	run := &Run{StartedAt: start.UTC()}
+	logger := slog.With("scheduleID", j.ID, "url", j.URL)
+
+	runCtx, cancel := context.WithTimeout(ctx, s.opts.RunTimeout)
+	page, err := s.opts.Analyze(runCtx, j.URL)
+	cancel()
+
+	var record *models.AnalysisRecord
+	if err != nil {
+		run.Error = err.Error()
+		metrics.ScheduledRuns.WithLabelValues("failed").Inc()
+		logger.Warn("scheduled analysis failed", "error", err)
+	} else {
+		metrics.ScheduledRuns.WithLabelValues("ok").Inc()
+		record = s.save(ctx, j, page, start)
+		run.ResultID = page.ResultID
+	}
+
+	run.Alerts = evaluate(j.Rules, s.previous(ctx, j), record, err)
+	run.Duration = time.Since(start).String()
+
+	if len(run.Alerts) > 0 {
+		metrics.ScheduleAlerts.Add(float64(len(run.Alerts)))
+		logger.Info("schedule raised alerts", "alerts", len(run.Alerts))
+
+		if j.WebhookURL != "" && s.opts.Sender != nil {
+			payload := &AlertPayload{
+				ScheduleID: j.ID,
+				URL:        j.URL,
+				RunAt:      run.StartedAt,
+				ResultID:   run.ResultID,
+				Alerts:     run.Alerts,
+				Analysis:   page,
+			}
+			delivery, err := s.opts.Sender.Send(ctx, j.WebhookURL, j.KeyID, EventAlert, payload)
+			run.Webhook = delivery
+			if err != nil {
+				logger.Warn("alert webhook delivery failed", "webhook", j.WebhookURL, "error", err)
+			}
+		}
+	}
+
+	s.finish(j, record, run)
+	return run
+}
+
+// charge counts one analysis against the quota of the key that created j.
+func (s *Scheduler) charge(j *job) error {
+	if j.KeyID == "" || s.opts.ChargeQuota == nil {
+		return nil
+	}
+	return s.opts.ChargeQuota(j.KeyID, 1)
+}
+
+// refuse records a scheduled run that charge refused.
+func (s *Scheduler) refuse(j *job, err error) {
+	metrics.ScheduledRuns.WithLabelValues("refused").Inc()
+	slog.Warn("scheduled analysis refused", "scheduleID", j.ID, "url", j.URL, "error", err)
+	s.finish(j, nil, &Run{StartedAt: time.Now().UTC(), Duration: "0s", Error: err.Error()})
+}
+
+func (s *Scheduler) save(ctx context.Context, j *job, page *models.PageAnalysis, start time.Time) *models.AnalysisRecord {
+	record := &models.AnalysisRecord{
+		ID:        uuid.New().String(),
+		URL:       j.URL,
+		RequestID: "schedule:" + j.ID,
+		CreatedAt: start.UTC(),
+		Analysis:  page,
+	}
+	if s.opts.Store == nil {
+		return record
+	}
+
+	page.ResultID = record.ID
+	if err := s.opts.Store.Save(ctx, record); err != nil {
+		page.ResultID = ""
+		slog.Error("failed to store scheduled result", "scheduleID", j.ID, "url", j.URL, "error", err)
+	}
+	return record
+}
+
+// previous returns the record change rules compare with. After a restart it is read
+// back from the store.
+func (s *Scheduler) previous(ctx context.Context, j *job) *models.AnalysisRecord {
+	s.mu.Lock()
+	previous, id := j.previous, j.LastResultID
+	s.mu.Unlock()
+
+	if previous != nil || id == "" || s.opts.Store == nil {
+		return previous
+	}
+	record, err := s.opts.Store.Get(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+func (s *Scheduler) finish(j *job, record *models.AnalysisRecord, run *Run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.running = false
+	if _, ok := s.jobs[j.ID]; !ok {
+		return // removed while running
+	}
+	j.LastRun = run
+	if record != nil {
+		j.previous = record
+		if record.Analysis.ResultID != "" {
+			j.LastResultID = record.ID
+		}
+	}
+	if err := s.persistLocked(); err != nil {
+		slog.Error("failed to persist schedules", "error", err)
+	}
+	s.notify()
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) validate(spec *JobSpec) (schedule, error) {
+	var fields []models.FieldError
+	invalid := func(field, format string, args ...any) {
+		fields = append(fields, models.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	spec.URL = strings.TrimSpace(spec.URL)
+	if u, err := url.ParseRequestURI(spec.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		invalid("url", "must be an absolute http or https URL")
+	}
+
+	var sched schedule
+	switch {
+	case spec.Cron == "" && spec.Interval == "":
+		invalid("cron", "either cron or interval is required")
+	case spec.Cron != "" && spec.Interval != "":
+		invalid("interval", "cannot be combined with cron")
+	case spec.Cron != "":
+		loc := time.UTC
+		if spec.Timezone != "" {
+			var err error
+			if loc, err = time.LoadLocation(spec.Timezone); err != nil {
+				invalid("timezone", "unknown time zone %q", spec.Timezone)
+				break
+			}
+		}
+		cron, err := parseCron(spec.Cron, loc)
+		if err != nil {
+			invalid("cron", "%v", err)
+			break
+		}
+		if cron.Next(time.Now()).IsZero() {
+			invalid("cron", "never matches a date")
+			break
+		}
+		sched = cron
+	default:
+		every, err := time.ParseDuration(spec.Interval)
+		if err != nil {
+			invalid("interval", "must be a duration such as 15m or 1h")
+			break
+		}
+		if every < MinInterval {
+			invalid("interval", "must be at least %s", MinInterval)
+			break
+		}
+		if spec.Timezone != "" {
+			invalid("timezone", "only applies to cron schedules")
+		}
+		sched = intervalSchedule{every: every}
+	}
+
+	for i, r := range spec.Rules {
+		if err := validateRule(r); err != nil {
+			invalid(fmt.Sprintf("rules[%d]", i), "%v", err)
+		}
+	}
+
+	if spec.WebhookURL != "" {
+		if u, err := url.ParseRequestURI(spec.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("webhook_url", "must be an absolute http or https URL")
+		} else if s.opts.Sender == nil {
+			invalid("webhook_url", "webhooks are not configured on this server")
+		}
+	}
+
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+	return sched, nil
+}
+
+func (s *Scheduler) load() error {
+	if s.opts.JobsPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.opts.JobsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schedules: %w", err)
+	}
+
+	var jobs []Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return fmt.Errorf("failed to parse schedules %s: %w", s.opts.JobsPath, err)
+	}
+	for _, stored := range jobs {
+		spec := stored.JobSpec
+		sched, err := s.validate(&spec)
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", stored.ID, err)
+		}
+		s.jobs[stored.ID] = &job{Job: stored, schedule: sched}
+	}
+	return nil
+}
+
+// persistLocked writes every job to JobsPath, replacing the file atomically.
+func (s *Scheduler) persistLocked() error {
+	if s.opts.JobsPath == "" {
+		return nil
+	}
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j.Job)
+	}
+	slices.SortFunc(jobs, func(a, b Job) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schedules: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.opts.JobsPath), filepath.Base(s.opts.JobsPath)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to persist schedules: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to persist schedules: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to persist schedules: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to persist schedules: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.opts.JobsPath); err != nil {
+		return fmt.Errorf("failed to persist schedules: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/models"
+	"web-analyzer/pkg/api"
+	"web-analyzer/pkg/metrics"
+)
+
+// apiKeyIDContextKey holds the authenticated key ID in the gin context. The analysis
+// callbacks read it too, to scope their deliveries.
+const apiKeyIDContextKey = "apiKeyID"
+
+// AuthMiddleware rejects requests without valid credentials and applies the key's rate limit.
+func AuthMiddleware(a *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, err := authenticate(c, a)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			metrics.APIKeyRequests.WithLabelValues("anonymous", "too_large").Inc()
+			apierror.Abort(c, http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Code:    models.CodeTooLarge,
+				Error:   "Request too large",
+				Details: fmt.Sprintf("signed request bodies are limited to %d bytes", tooLarge.Limit),
+			})
+			return
+		}
+		if err != nil {
+			metrics.APIKeyRequests.WithLabelValues("anonymous", "unauthorized").Inc()
+			apierror.Abort(c, http.StatusUnauthorized, models.ErrorResponse{
+				Code:    models.CodeUnauthorized,
+				Error:   "Unauthorized",
+				Details: err.Error(),
+			})
+			return
+		}
+
+		if limiter := a.Limiter(key.ID); limiter != nil && !applyRateLimit(c, limiter, key.ID) {
+			metrics.APIKeyRequests.WithLabelValues(key.ID, "rate_limited").Inc()
+			return
+		}
+
+		metrics.APIKeyRequests.WithLabelValues(key.ID, "allowed").Inc()
+		c.Set(apiKeyIDContextKey, key.ID)
+		c.Next()
+	}
+}
+
+// QuotaMiddleware counts analyses against the authenticated key's daily quota.
+// It must run after AuthMiddleware.
+func QuotaMiddleware(a *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if chargeQuota(c, a, 1) {
+			c.Next()
+		}
+	}
+}
+
+// chargeQuota counts n analyses against the authenticated key's daily quota and
+// aborts with 429 if they do not all fit.
+func chargeQuota(c *gin.Context, a *auth.Authenticator, n int) bool {
+	quota, err := a.ChargeQuota(c.GetString(apiKeyIDContextKey), n)
+	if quota.Limit > 0 {
+		c.Header("X-Quota-Limit", strconv.Itoa(quota.Limit))
+		c.Header("X-Quota-Remaining", strconv.Itoa(quota.Remaining))
+	}
+
+	var limitErr *auth.LimitError
+	if errors.As(err, &limitErr) {
+		apierror.Abort(c, http.StatusTooManyRequests, models.ErrorResponse{
+			Code:    limitErr.Code,
+			Error:   "Daily quota exceeded",
+			Details: limitErr.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func authenticate(c *gin.Context, a *auth.Authenticator) (*auth.APIKey, error) {
+	if c.GetHeader(api.HeaderSignature) != "" {
+		return a.VerifySignature(c.Writer, c.Request)
+	}
+
+	presented := c.GetHeader(api.HeaderAPIKey)
+	if presented == "" {
+		if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			presented = strings.TrimSpace(bearer)
+		}
+	}
+	return a.LookupKey(presented)
+}
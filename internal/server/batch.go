@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/models"
+)
+
+// maxBatchBodyBytes matches the limit on signed bodies, which are read before routing.
+const maxBatchBodyBytes = analysis.MaxRequestBodyBytes
+
+// batchHandlers serves POST /api/v1/analyze/batch.
+type batchHandlers struct {
+	// auth charges every request of a batch against the key's daily quota. Nil when
+	// the API is open.
+	auth *auth.Authenticator
+}
+
+func (h batchHandlers) register(api *gin.RouterGroup) {
+	api.POST("/analyze/batch", h.analyze)
+}
+
+func (h batchHandlers) analyze(c *gin.Context) {
+	var batch models.BatchAnalyzeRequest
+	dec := json.NewDecoder(http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&batch); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Write(c, http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Code:    models.CodeTooLarge,
+				Error:   "Request too large",
+				Details: fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit),
+			})
+			return
+		}
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.CodeInvalidInput,
+			Error:   "Invalid batch",
+			Details: "Request body must be a JSON batch",
+			Fields:  []models.FieldError{{Field: "body", Message: "invalid JSON: " + err.Error()}},
+		})
+		return
+	}
+
+	if n := len(batch.Requests); n == 0 || n > analysis.MaxBatchSize {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:  models.CodeInvalidInput,
+			Error: "Invalid batch",
+			Fields: []models.FieldError{{
+				Field:   "requests",
+				Message: fmt.Sprintf("must hold between 1 and %d requests, got %d", analysis.MaxBatchSize, n),
+			}},
+		})
+		return
+	}
+
+	if h.auth != nil && !chargeQuota(c, h.auth, len(batch.Requests)) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	requestID := c.GetString("requestID")
+	results := make([]models.BatchResult, len(batch.Requests))
+	sem := make(chan struct{}, analysis.BatchConcurrency)
+	var wg sync.WaitGroup
+	for i, req := range batch.Requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i].URL = req.URL
+			result, err := analysis.Analyze(ctx, req, requestID)
+			if err != nil {
+				slog.Error("analysis failed..", "handler", "batch", "requestID", requestID, "url", req.URL, "error", err)
+				_, resp := analysis.ResponseFor(err)
+				results[i].Error = &resp
+				return
+			}
+			results[i].Analysis = result
+		}()
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusOK, models.BatchAnalyzeResponse{Results: results})
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"bytes"
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPISpec is the OpenAPI 3 document describing every route registerRoutes serves.
+// It is kept in step with the handlers by test/internal/server/openapi_test.go.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// OpenAPISpec returns a copy of the OpenAPI 3 document of the HTTP API.
+func OpenAPISpec() []byte {
+	return bytes.Clone(openAPISpec)
+}
+
+// openAPIHandler serves the spec. It is public, like /health, so clients can discover
+// how to authenticate.
+func openAPIHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/ratelimit"
+	"web-analyzer/pkg/metrics"
+)
+
+// Route groups that can be given their own rate limiter in Options.RateLimiters.
+const (
+	RouteGroupAPI    = "api"    // /api/v1
+	RouteGroupLegacy = "legacy" // /url_analyze
+)
+
+// RateLimitMiddleware throttles requests per API key, or per client IP for
+// unauthenticated requests, and reports the limiter state in X-RateLimit-* headers.
+func RateLimitMiddleware(group string, limiter ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := ratelimit.ClientKey(c.GetString(apiKeyIDContextKey), c.ClientIP())
+		if !applyRateLimit(c, limiter, key) {
+			metrics.RateLimited.WithLabelValues(group).Inc()
+			return
+		}
+		c.Next()
+	}
+}
+
+// applyRateLimit sets the rate limit headers and aborts with 429 when the request is not
+// allowed. Limiter errors fail open so a broken shared store does not take the API down.
+func applyRateLimit(c *gin.Context, limiter ratelimit.Limiter, key string) bool {
+	result, err := limiter.Allow(c.Request.Context(), key)
+	if err != nil {
+		slog.Warn("rate limiter unavailable, allowing request", "key", key, "error", err)
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if result.Allowed {
+		return true
+	}
+
+	c.Header("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+	apierror.Abort(c, http.StatusTooManyRequests, models.ErrorResponse{
+		Code:    models.CodeRateLimited,
+		Error:   "Rate limit exceeded",
+		Details: "Too many requests, retry after the time given in the Retry-After header",
+	})
+	return false
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -15,27 +15,62 @@ import (
 	"log/slog"
 
 	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/ratelimit"
+	"web-analyzer/internal/scheduler"
+	"web-analyzer/internal/webhook"
+	"web-analyzer/pkg/api"
 	"web-analyzer/pkg/metrics"
 )
 
+// Options configures the optional parts of the router.
+type Options struct {
+	// Auth protects the API routes. Nil leaves them open.
+	Auth *auth.Authenticator
+	// CORSOrigins lists the origins allowed to call the API with credentials.
+	// When empty any origin may call it, without credentials.
+	CORSOrigins []string
+	// CORSMaxAge is how long browsers may cache preflight responses, 12 hours when zero.
+	CORSMaxAge time.Duration
+	// RateLimiters throttles each route group (RouteGroupAPI, RouteGroupLegacy)
+	// per API key or client IP. Groups without a limiter are not throttled.
+	RateLimiters map[string]ratelimit.Limiter
+	// TrustedProxies lists the proxy addresses whose X-Forwarded-For header is used
+	// to determine the client IP. By default no proxy is trusted.
+	TrustedProxies []string
+	// Scheduler enables the /api/v1/schedules routes. Nil leaves them unregistered.
+	Scheduler *scheduler.Scheduler
+	// Deliveries enables the /api/v1/webhooks/deliveries routes listing webhook and
+	// callback deliveries. Nil leaves them unregistered.
+	Deliveries *webhook.DeliveryLog
+}
+
 func SetupRouter() *gin.Engine {
+	return SetupRouterWithOptions(Options{})
+}
 
-	setupLogger()
+func SetupRouterWithOptions(opts Options) *gin.Engine {
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		slog.Error("invalid trusted proxies, trusting none", "error", err)
+		_ = router.SetTrustedProxies(nil)
+	}
 	router.Use(
 		gin.Recovery(),
 		requestIDMiddleware(),
 		loggerMiddleware(),
-		configureCORS(),
+		configureCORS(opts.CORSOrigins, opts.CORSMaxAge),
 	)
 	metrics.InitMetrics()
-	registerRoutes(router)
+	registerRoutes(router, opts)
 
 	return router
 }
 
-func RunServer(router *gin.Engine, addr string) {
+// RunServer serves router on addr until SIGINT or SIGTERM, then gives in-flight
+// requests up to shutdownTimeout to finish.
+func RunServer(router *gin.Engine, addr string, shutdownTimeout time.Duration) {
 
 	srv := &http.Server{
 		Addr:    addr,
@@ -56,28 +91,61 @@ func RunServer(router *gin.Engine, addr string) {
 
 	slog.Info("shutting down server...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("server forced to shutdown", "error", err)
 	}
+	// Accepted callback analyses still owe their callers a result.
+	if err := analysis.DrainCallbacks(ctx); err != nil {
+		slog.Error("pending callbacks abandoned", "error", err)
+	}
 
 	slog.Info("server exited")
 }
 
-func registerRoutes(r *gin.Engine) {
+func registerRoutes(r *gin.Engine, opts Options) {
 	// Health and metrics
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	r.GET("/health", healthCheckHandler)
+	r.GET("/api/v1/openapi.json", openAPIHandler)
 
-	// Backward compatibility
-	r.GET("/url_analyze", analysis.HandleAnalyze)
+	analyze := []gin.HandlerFunc{analysis.HandleAnalyze}
+	if opts.Auth != nil {
+		analyze = []gin.HandlerFunc{QuotaMiddleware(opts.Auth), analysis.HandleAnalyze}
+	}
 
+	// Backward compatibility
+	legacy := r.Group("/")
 	// API v1
 	api := r.Group("/api/v1")
+
+	if opts.Auth != nil {
+		legacy.Use(AuthMiddleware(opts.Auth))
+		api.Use(AuthMiddleware(opts.Auth))
+	}
+	if limiter, ok := opts.RateLimiters[RouteGroupLegacy]; ok {
+		legacy.Use(RateLimitMiddleware(RouteGroupLegacy, limiter))
+	}
+	if limiter, ok := opts.RateLimiters[RouteGroupAPI]; ok {
+		api.Use(RateLimitMiddleware(RouteGroupAPI, limiter))
+	}
+
+	legacy.GET("/url_analyze", analyze...)
 	{
-		api.GET("/analyze", analysis.HandleAnalyze)
+		api.GET("/analyze", analyze...)
+		api.POST("/analyze", analyze...)
+		api.GET("/results/:id", analysis.HandleGetResult)
+		api.GET("/history", analysis.HandleHistory)
+		api.GET("/diff", analysis.HandleDiff)
+	}
+	batchHandlers{auth: opts.Auth}.register(api)
+	if opts.Scheduler != nil {
+		scheduleHandlers{scheduler: opts.Scheduler}.register(api)
+	}
+	if opts.Deliveries != nil {
+		deliveryHandlers{log: opts.Deliveries}.register(api)
 	}
 }
 
@@ -112,21 +180,28 @@ func healthCheckHandler(c *gin.Context) {
 	})
 }
 
-func setupLogger() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-	slog.SetDefault(logger)
-}
+func configureCORS(origins []string, maxAge time.Duration) gin.HandlerFunc {
+	if maxAge == 0 {
+		maxAge = 12 * time.Hour
+	}
 
-func configureCORS() gin.HandlerFunc {
+	config := cors.Config{
+		AllowMethods: []string{"GET", "POST", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "Cache-Control", "Pragma",
+			api.HeaderAPIKey, api.HeaderKeyID, api.HeaderTimestamp, api.HeaderNonce, api.HeaderSignature},
+		ExposeHeaders: []string{"Content-Length", "X-Request-ID", "Retry-After",
+			"X-Quota-Limit", "X-Quota-Remaining", "X-Result-ID", "X-Cache", "Age",
+			"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
+		MaxAge: maxAge,
+	}
 
-	return cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	})
+	// Credentials are only allowed for an explicit allowlist, never for a wildcard origin.
+	if len(origins) == 0 {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = origins
+		config.AllowCredentials = true
+	}
+
+	return cors.New(config)
 }
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/scheduler"
+)
+
+const maxScheduleBodyBytes = 64 << 10
+
+// scheduleHandlers serves the /api/v1/schedules routes.
+type scheduleHandlers struct {
+	scheduler *scheduler.Scheduler
+}
+
+func (h scheduleHandlers) register(api *gin.RouterGroup) {
+	api.POST("/schedules", h.create)
+	api.GET("/schedules", h.list)
+	api.GET("/schedules/:id", h.get)
+	api.DELETE("/schedules/:id", h.remove)
+	api.POST("/schedules/:id/run", h.run)
+}
+
+func (h scheduleHandlers) create(c *gin.Context) {
+	var spec scheduler.JobSpec
+	dec := json.NewDecoder(http.MaxBytesReader(c.Writer, c.Request.Body, maxScheduleBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:    models.CodeInvalidInput,
+			Error:   "Invalid schedule",
+			Details: "Request body must be a JSON schedule",
+			Fields:  []models.FieldError{{Field: "body", Message: err.Error()}},
+		})
+		return
+	}
+
+	job, err := h.scheduler.Add(spec, c.GetString(apiKeyIDContextKey))
+	var validationErr *scheduler.ValidationError
+	if errors.As(err, &validationErr) {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:   models.CodeInvalidInput,
+			Error:  "Invalid schedule",
+			Fields: validationErr.Fields,
+		})
+		return
+	}
+	if err != nil {
+		apierror.Write(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    models.CodeInternal,
+			Error:   "Failed to create schedule",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Location", "/api/v1/schedules/"+job.ID)
+	c.JSON(http.StatusCreated, job)
+}
+
+func (h scheduleHandlers) list(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"schedules": h.scheduler.List()})
+}
+
+func (h scheduleHandlers) get(c *gin.Context) {
+	job, err := h.scheduler.Get(c.Param("id"))
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (h scheduleHandlers) remove(c *gin.Context) {
+	if err := h.scheduler.Remove(c.Param("id")); err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// run triggers a job immediately and responds with the outcome once it finished.
+func (h scheduleHandlers) run(c *gin.Context) {
+	run, err := h.scheduler.RunNow(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+func scheduleError(c *gin.Context, err error) {
+	var limitErr *auth.LimitError
+	switch {
+	case errors.As(err, &limitErr):
+		apierror.Write(c, http.StatusTooManyRequests, models.ErrorResponse{
+			Code:    limitErr.Code,
+			Error:   "Daily quota exceeded",
+			Details: limitErr.Error(),
+		})
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		apierror.Write(c, http.StatusNotFound, models.ErrorResponse{Code: models.CodeNotFound, Error: "Schedule not found"})
+	case errors.Is(err, scheduler.ErrJobRunning):
+		apierror.Write(c, http.StatusConflict, models.ErrorResponse{
+			Code:    models.CodeConflict,
+			Error:   "Schedule is already running",
+			Details: "Wait for the current run to finish",
+		})
+	default:
+		apierror.Write(c, http.StatusInternalServerError, models.ErrorResponse{
+			Code:    models.CodeInternal,
+			Error:   "Schedule operation failed",
+			Details: err.Error(),
+		})
+	}
+}
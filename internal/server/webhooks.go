@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/webhook"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// deliveryHandlers serves the /api/v1/webhooks/deliveries routes.
+type deliveryHandlers struct {
+	log *webhook.DeliveryLog
+}
+
+func (h deliveryHandlers) register(api *gin.RouterGroup) {
+	api.GET("/webhooks/deliveries", h.list)
+	api.GET("/webhooks/deliveries/:id", h.get)
+}
+
+func (h deliveryHandlers) list(c *gin.Context) {
+	var fields []models.FieldError
+
+	status := c.Query("status")
+	switch status {
+	case "", webhook.StatusPending, webhook.StatusDelivered, webhook.StatusFailed:
+	default:
+		fields = append(fields, models.FieldError{Field: "status", Message: "must be pending, delivered or failed"})
+	}
+
+	limit := defaultDeliveryLimit
+	if raw, ok := c.GetQuery("limit"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxDeliveryLimit {
+			fields = append(fields, models.FieldError{Field: "limit", Message: "must be between 1 and " + strconv.Itoa(maxDeliveryLimit)})
+		}
+		limit = v
+	}
+
+	if len(fields) > 0 {
+		apierror.Write(c, http.StatusBadRequest, models.ErrorResponse{
+			Code:   models.CodeInvalidInput,
+			Error:  "Invalid delivery query",
+			Fields: fields,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": h.log.List(c.GetString(apiKeyIDContextKey), status, limit)})
+}
+
+func (h deliveryHandlers) get(c *gin.Context) {
+	delivery, ok := h.log.Get(c.GetString(apiKeyIDContextKey), c.Param("id"))
+	if !ok {
+		apierror.Write(c, http.StatusNotFound, models.ErrorResponse{
+			Code:    models.CodeNotFound,
+			Error:   "Delivery not found",
+			Details: "No delivery of this key has this id, or it was evicted from the delivery log",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
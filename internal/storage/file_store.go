@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"web-analyzer/internal/models"
+)
+
+// compactThreshold is the number of obsolete log entries tolerated before the
+// file is rewritten, as long as they also outnumber the live records.
+const compactThreshold = 256
+
+// logEntry is one line of the store file.
+type logEntry struct {
+	Op     string                 `json:"op"` // "put" or "delete"
+	ID     string                 `json:"id,omitempty"`
+	Record *models.AnalysisRecord `json:"record,omitempty"`
+}
+
+// FileStore is an embedded store backed by an append-only JSON lines file. All live
+// records are indexed in memory, so the retention policy also bounds memory use. The
+// file is compacted once deleted entries outweigh the live ones.
+type FileStore struct {
+	mu      sync.RWMutex
+	path    string
+	file    *os.File
+	index   *index
+	garbage int // log entries that no longer describe a live record
+}
+
+// OpenFileStore opens or creates the store file at path and loads its records.
+func OpenFileStore(path string, retention Retention) (*FileStore, error) {
+	s := &FileStore{path: path, index: newIndex(retention)}
+
+	// Replaying applies the current retention policy; compacting drops what it evicted.
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileStore) load() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open result store: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry logEntry
+			if jsonErr := json.Unmarshal(line, &entry); jsonErr != nil {
+				// A torn final write after a crash; everything before it is intact.
+				slog.Warn("skipping unreadable result store entry", "path", s.path, "line", lineNo, "error", jsonErr)
+			} else {
+				s.replay(entry)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read result store: %w", err)
+		}
+	}
+}
+
+func (s *FileStore) replay(entry logEntry) {
+	switch entry.Op {
+	case "put":
+		if entry.Record != nil {
+			s.index.put(entry.Record)
+		}
+	case "delete":
+		s.index.remove(entry.ID)
+	}
+}
+
+func (s *FileStore) Save(_ context.Context, record *models.AnalysisRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The record is only indexed once it is on disk, so a failed write leaves memory
+	// and the file agreeing.
+	if err := s.append([]logEntry{{Op: "put", Record: record}}); err != nil {
+		return err
+	}
+	evicted := s.index.put(record)
+	if len(evicted) == 0 {
+		return nil
+	}
+
+	entries := make([]logEntry, 0, len(evicted))
+	for _, id := range evicted {
+		entries = append(entries, logEntry{Op: "delete", ID: id})
+	}
+	if err := s.append(entries); err != nil {
+		// Replaying the put applies the same retention policy, so the evictions are
+		// not lost; the record itself is saved.
+		slog.Warn("failed to record result store evictions", "path", s.path, "error", err)
+	}
+
+	// Each eviction leaves its put and its delete entry behind.
+	s.garbage += 2 * len(evicted)
+	if s.garbage >= compactThreshold && s.garbage > len(s.index.records) {
+		if err := s.compact(); err != nil {
+			// The old file is still open and complete; compaction is retried on a later save.
+			slog.Warn("result store compaction failed", "path", s.path, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) Get(_ context.Context, id string) (*models.AnalysisRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.get(id)
+}
+
+func (s *FileStore) History(_ context.Context, url string, page Page) ([]*models.AnalysisRecord, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records, total := s.index.history(url, page)
+	return records, total, nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *FileStore) append(entries []logEntry) error {
+	if s.file == nil {
+		return fmt.Errorf("result store is closed")
+	}
+
+	var buf []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	info, err := s.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to write result store: %w", err)
+	}
+	if _, err := s.file.Write(buf); err != nil {
+		// Drop a partial line so the next entry does not get appended to it.
+		_ = s.file.Truncate(info.Size())
+		return fmt.Errorf("failed to write result store: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		_ = s.file.Truncate(info.Size())
+		return fmt.Errorf("failed to write result store: %w", err)
+	}
+	return nil
+}
+
+// compact rewrites the file with only the live records and keeps the rewritten file
+// open for appending. On error the current file stays in place and open.
+func (s *FileStore) compact() error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to compact result store: %w", err)
+	}
+	discard := func(err error) error {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to compact result store: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, record := range s.index.all() {
+		if err := enc.Encode(logEntry{Op: "put", Record: record}); err != nil {
+			return discard(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return discard(err)
+	}
+	if err := f.Sync(); err != nil {
+		return discard(err)
+	}
+	// f follows the file through the rename, so it needs no reopening.
+	if err := os.Rename(tmp, s.path); err != nil {
+		return discard(err)
+	}
+
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			slog.Warn("failed to close replaced result store file", "path", s.path, "error", err)
+		}
+	}
+	s.file = f
+	s.garbage = 0
+	return nil
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"web-analyzer/internal/models"
+)
+
+// ErrNotFound is returned by Get for unknown or expired results.
+var ErrNotFound = errors.New("result not found")
+
+// Store persists analysis results. Implementations must be safe for concurrent use.
+type Store interface {
+	Save(ctx context.Context, record *models.AnalysisRecord) error
+	Get(ctx context.Context, id string) (*models.AnalysisRecord, error)
+	// History returns one page of the results stored for url, newest first, and the total count.
+	History(ctx context.Context, url string, page Page) ([]*models.AnalysisRecord, int, error)
+	Close() error
+}
+
+// Page selects a slice of a result list.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Retention bounds what a store keeps. Zero fields disable the corresponding bound.
+type Retention struct {
+	MaxAge     time.Duration // results older than this are dropped
+	MaxPerURL  int           // only the newest results per URL are kept
+	MaxRecords int           // only the newest results overall are kept
+}
+
+// MemoryStore keeps results in process memory. They are lost on restart.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	index *index
+}
+
+func NewMemoryStore(retention Retention) *MemoryStore {
+	return &MemoryStore{index: newIndex(retention)}
+}
+
+func (s *MemoryStore) Save(_ context.Context, record *models.AnalysisRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index.put(record)
+	return nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, id string) (*models.AnalysisRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.get(id)
+}
+
+func (s *MemoryStore) History(_ context.Context, url string, page Page) ([]*models.AnalysisRecord, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records, total := s.index.history(url, page)
+	return records, total, nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// index holds the records of a store in insertion order and applies the retention policy.
+// It is not safe for concurrent use.
+type index struct {
+	retention Retention
+	now       func() time.Time
+	records   map[string]*models.AnalysisRecord
+	byURL     map[string][]string // record IDs, oldest first
+	order     []string            // record IDs, oldest first
+}
+
+func newIndex(retention Retention) *index {
+	return &index{
+		retention: retention,
+		now:       time.Now,
+		records:   make(map[string]*models.AnalysisRecord),
+		byURL:     make(map[string][]string),
+	}
+}
+
+// put adds record and returns the IDs evicted by the retention policy.
+func (ix *index) put(record *models.AnalysisRecord) []string {
+	if _, exists := ix.records[record.ID]; exists {
+		ix.remove(record.ID)
+	}
+	ix.records[record.ID] = record
+	ix.byURL[record.URL] = append(ix.byURL[record.URL], record.ID)
+	ix.order = append(ix.order, record.ID)
+
+	var evicted []string
+	evict := func(id string) {
+		ix.remove(id)
+		evicted = append(evicted, id)
+	}
+
+	if max := ix.retention.MaxPerURL; max > 0 {
+		for len(ix.byURL[record.URL]) > max {
+			evict(ix.byURL[record.URL][0])
+		}
+	}
+	if max := ix.retention.MaxRecords; max > 0 {
+		for len(ix.order) > max {
+			evict(ix.order[0])
+		}
+	}
+	for len(ix.order) > 0 && ix.expired(ix.records[ix.order[0]]) {
+		evict(ix.order[0])
+	}
+
+	return evicted
+}
+
+func (ix *index) remove(id string) {
+	record, ok := ix.records[id]
+	if !ok {
+		return
+	}
+	delete(ix.records, id)
+
+	ids := slices.DeleteFunc(ix.byURL[record.URL], func(other string) bool { return other == id })
+	if len(ids) == 0 {
+		delete(ix.byURL, record.URL)
+	} else {
+		ix.byURL[record.URL] = ids
+	}
+	ix.order = slices.DeleteFunc(ix.order, func(other string) bool { return other == id })
+}
+
+func (ix *index) get(id string) (*models.AnalysisRecord, error) {
+	record, ok := ix.records[id]
+	if !ok || ix.expired(record) {
+		return nil, ErrNotFound
+	}
+	return record, nil
+}
+
+func (ix *index) history(url string, page Page) ([]*models.AnalysisRecord, int) {
+	ids := ix.byURL[url]
+
+	live := make([]*models.AnalysisRecord, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		if record := ix.records[ids[i]]; !ix.expired(record) {
+			live = append(live, record)
+		}
+	}
+
+	total := len(live)
+	start := min(page.Offset, total)
+	end := total
+	if page.Limit > 0 {
+		end = min(start+page.Limit, total)
+	}
+	return live[start:end], total
+}
+
+func (ix *index) expired(record *models.AnalysisRecord) bool {
+	return ix.retention.MaxAge > 0 && ix.now().Sub(record.CreatedAt) > ix.retention.MaxAge
+}
+
+// all returns the live records, oldest first.
+func (ix *index) all() []*models.AnalysisRecord {
+	records := make([]*models.AnalysisRecord, 0, len(ix.order))
+	for _, id := range ix.order {
+		records = append(records, ix.records[id])
+	}
+	return records
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// charsetSniffLen is the number of bytes the HTML spec prescan examines for a <meta charset>.
+const charsetSniffLen = 1024
+
+var byteOrderMarks = [][]byte{
+	{0xEF, 0xBB, 0xBF}, // UTF-8
+	{0xFE, 0xFF},       // UTF-16BE
+	{0xFF, 0xFE},       // UTF-16LE
+}
+
+// DecodeCharset determines the character encoding of an HTML body from its BOM,
+// the Content-Type header, a <meta charset> declaration or the content itself,
+// and returns a reader producing UTF-8 along with the canonical encoding name.
+func DecodeCharset(body io.Reader, contentType string) (io.Reader, string, error) {
+	bufReader := bufio.NewReaderSize(body, charsetSniffLen)
+
+	peek, err := bufReader.Peek(charsetSniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", err
+	}
+
+	enc, name, _ := charset.DetermineEncoding(peek, contentType)
+
+	// The parser would otherwise keep the BOM as text in front of the document.
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(peek, bom) {
+			_, _ = bufReader.Discard(len(bom))
+			break
+		}
+	}
+
+	if enc == encoding.Nop {
+		return bufReader, name, nil
+	}
+
+	return transform.NewReader(bufReader, enc.NewDecoder()), name, nil
+}
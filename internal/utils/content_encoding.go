@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// AcceptEncoding advertises every content coding DecodeContentEncoding understands.
+const AcceptEncoding = "br, gzip, deflate, zstd"
+
+// maxZstdWindow bounds the window a zstd frame may ask the decoder to allocate. Frame
+// headers are read before any body limit applies; 8 MiB is what RFC 9659 requires
+// HTTP senders to stay within.
+const maxZstdWindow = 8 << 20
+
+// DecodeContentEncoding wraps reader with decoders for a Content-Encoding header value.
+// Codings are listed in the order they were applied, so they are removed in reverse.
+func DecodeContentEncoding(reader io.Reader, contentEncoding string) (io.Reader, error) {
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+
+		var err error
+		reader, err = decodeContent(reader, encoding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s content: %w", encoding, err)
+		}
+	}
+
+	return reader, nil
+}
+
+// ByteCounter counts the bytes read through it.
+type ByteCounter struct {
+	Reader io.Reader
+	N      int64
+}
+
+func (c *ByteCounter) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.N += int64(n)
+	return n, err
+}
+
+func decodeContent(reader io.Reader, encoding string) (io.Reader, error) {
+	switch encoding {
+	case "", "identity":
+		return reader, nil
+	case "gzip", "x-gzip":
+		bufReader := bufio.NewReader(reader)
+		if isEmpty(bufReader) {
+			return bufReader, nil
+		}
+		return gzip.NewReader(bufReader)
+	case "br":
+		return brotli.NewReader(reader), nil
+	case "deflate":
+		return newDeflateReader(reader)
+	case "zstd":
+		// A single-threaded decoder runs synchronously, so it needs no Close to release goroutines.
+		return zstd.NewReader(reader, zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(maxZstdWindow), zstd.WithDecoderMaxMemory(maxZstdWindow))
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// newDeflateReader handles both zlib-wrapped deflate, as RFC 9110 requires, and the
+// raw deflate streams that some servers send instead.
+func newDeflateReader(reader io.Reader) (io.Reader, error) {
+	bufReader := bufio.NewReader(reader)
+
+	if isEmpty(bufReader) {
+		return bufReader, nil
+	}
+	header, err := bufReader.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	if isZlibHeader(header) {
+		return zlib.NewReader(bufReader)
+	}
+	return flate.NewReader(bufReader), nil
+}
+
+// isEmpty reports whether r has no bytes left. Empty bodies, common on error pages,
+// carry no compression header and decode to nothing.
+func isEmpty(r *bufio.Reader) bool {
+	_, err := r.Peek(1)
+	return err == io.EOF
+}
+
+func isZlibHeader(header []byte) bool {
+	cmf, flg := header[0], header[1]
+	return cmf&0x0f == 8 && (uint16(cmf)<<8|uint16(flg))%31 == 0
+}
@@ -2,31 +2,95 @@ package utils
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"golang.org/x/net/html"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
-	"sync"
 	"time"
 
 	"web-analyzer/internal/models"
+	"web-analyzer/internal/netguard"
 )
 
-var (
-	checkedLinks sync.Map // Maintain already checked links
-)
+// TraverseOptions controls what TraverseHTML extracts. The zero value computes every
+// section, without limits, and sends every link.
+type TraverseOptions struct {
+	// Limits stop descending, counting or emitting links once reached and mark the
+	// analysis as truncated.
+	Limits   models.ParseLimits
+	Sections models.Sections
+	// SkipLinkCheck counts links without sending them to linksChan.
+	SkipLinkCheck bool
+	// LinkFilter selects the links sent to linksChan; the others are counted as skipped.
+	LinkFilter *LinkFilter
+	// Context, when set, stops the sending once it is done: the remaining links are
+	// counted as skipped, so the walk never blocks on a linksChan nobody reads.
+	Context context.Context
+}
+
+// TraverseHTML walks the document, recording the selected sections in analysis and
+// sending the links that should be checked to linksChan.
+func TraverseHTML(n *html.Node, analysis *models.PageAnalysis, baseURL string,
+	linksChan chan<- models.LinkInfo, opts TraverseOptions) {
+
+	t := &traverser{
+		analysis:  analysis,
+		baseURL:   baseURL,
+		linksChan: linksChan,
+		opts:      opts,
+		emitted:   make(map[string]bool),
+	}
+	t.walk(n, 0)
+}
+
+type traverser struct {
+	analysis  *models.PageAnalysis
+	baseURL   string
+	linksChan chan<- models.LinkInfo
+	opts      TraverseOptions
+	emitted   map[string]bool // links already sent, each is checked once per page
+	nodes     int
+	links     int
+	stopped   bool
+}
+
+func (t *traverser) walk(n *html.Node, depth int) {
+	if t.stopped {
+		return
+	}
+
+	analysis := t.analysis
+	baseURL := t.baseURL
+	limits := t.opts.Limits
+	sections := t.opts.Sections
 
-func TraverseHTML(n *html.Node, analysis *models.PageAnalysis, baseURL string, linksChan chan<- models.LinkInfo) {
 	if n.Type == html.ElementNode {
-		switch n.Data {
-		case "title":
+		t.nodes++
+		if limits.MaxNodes > 0 && t.nodes > limits.MaxNodes {
+			analysis.Truncate(fmt.Sprintf("document exceeded %d element nodes", limits.MaxNodes))
+			t.stopped = true
+			return
+		}
+		if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+			analysis.Truncate(fmt.Sprintf("document nesting exceeded depth %d", limits.MaxDepth))
+			return
+		}
+
+		if sections.Has(models.SectionMixedContent) {
+			recordMixedContent(n, analysis, baseURL)
+		}
+
+		switch {
+		case n.Data == "title" && sections.Has(models.SectionTitle):
 			if n.FirstChild != nil {
 				analysis.Title = n.FirstChild.Data
 			}
-		case "h1", "h2", "h3", "h4", "h5", "h6":
+		case isHeading(n.Data) && sections.Has(models.SectionHeadings):
 			analysis.Headings[n.Data]++
-		case "a":
+		case n.Data == "a" && sections.Has(models.SectionLinks):
 			for _, attr := range n.Attr {
 				if attr.Key == "href" {
 					linkURL := attr.Val
@@ -34,6 +98,12 @@ func TraverseHTML(n *html.Node, analysis *models.PageAnalysis, baseURL string, l
 						continue
 					}
 
+					if limits.MaxLinks > 0 && t.links >= limits.MaxLinks {
+						analysis.Truncate(fmt.Sprintf("page exceeded %d links", limits.MaxLinks))
+						continue
+					}
+					t.links++
+
 					isExternal := IsExternalLink(linkURL, baseURL)
 					if isExternal {
 						analysis.ExternalLinks++
@@ -41,14 +111,22 @@ func TraverseHTML(n *html.Node, analysis *models.PageAnalysis, baseURL string, l
 						analysis.InternalLinks++
 					}
 
-					linksChan <- models.LinkInfo{
-						URL:        NormalizeURL(linkURL, baseURL),
-						IsExternal: isExternal,
-						BaseURL:    baseURL,
+					normalized := NormalizeURL(linkURL, baseURL)
+					if t.opts.SkipLinkCheck || !t.opts.LinkFilter.Allows(normalized) {
+						analysis.SkippedLinks++
+						continue
+					}
+					if t.emitted[normalized] {
+						continue
+					}
+					t.emitted[normalized] = true
+
+					if !t.send(models.LinkInfo{URL: normalized, IsExternal: isExternal, BaseURL: baseURL}) {
+						analysis.SkippedLinks++
 					}
 				}
 			}
-		case "form":
+		case n.Data == "form" && sections.Has(models.SectionLoginForm):
 			isLoginForm := false
 			for _, attr := range n.Attr {
 				if attr.Key == "action" && strings.Contains(strings.ToLower(attr.Val), "login") {
@@ -81,7 +159,7 @@ func TraverseHTML(n *html.Node, analysis *models.PageAnalysis, baseURL string, l
 			if isLoginForm {
 				analysis.HasLoginForm = true
 			}
-		case "meta":
+		case n.Data == "meta" && sections.Has(models.SectionMetaTags):
 			if analysis.MetaTags == nil {
 				analysis.MetaTags = make(map[string]string)
 			}
@@ -101,73 +179,152 @@ func TraverseHTML(n *html.Node, analysis *models.PageAnalysis, baseURL string, l
 		}
 	}
 
+	childDepth := depth
+	if n.Type == html.ElementNode {
+		childDepth++
+	}
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		TraverseHTML(c, analysis, baseURL, linksChan)
+		t.walk(c, childDepth)
 	}
 }
 
-func CheckLink(link models.LinkInfo, analysis *models.PageAnalysis) {
-	if _, exists := checkedLinks.Load(link.URL); exists {
-		return
+// send sends link to linksChan, unless the traversal context is done first.
+func (t *traverser) send(link models.LinkInfo) bool {
+	ctx := t.opts.Context
+	if ctx == nil {
+		t.linksChan <- link
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case t.linksChan <- link:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func isHeading(tag string) bool {
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return true
 	}
-	checkedLinks.Store(link.URL, true)
+	return false
+}
+
+// DefaultUserAgent identifies the analyzer to the sites it fetches.
+const DefaultUserAgent = "WebAnalyzer/1.0"
+
+// LinkChecker checks links with a HEAD request.
+type LinkChecker struct {
+	Timeout      time.Duration // per link
+	MaxRedirects int
+	UserAgent    string
+}
 
+// DefaultLinkChecker returns the checker analyses use unless configured otherwise.
+func DefaultLinkChecker() LinkChecker {
+	return LinkChecker{
+		Timeout:      5 * time.Second,
+		MaxRedirects: 10,
+		UserAgent:    DefaultUserAgent,
+	}
+}
+
+// Check records the status of link in analysis, bounded by ctx as well as by the
+// checker's timeout, and reports whether it was recorded, see Result. It must not run
+// concurrently with other writes to analysis; concurrent checkers use Result and
+// aggregate the outcomes.
+func (lc LinkChecker) Check(ctx context.Context, link models.LinkInfo, analysis *models.PageAnalysis) bool {
+	result, ok := lc.Result(ctx, link)
+	if ok {
+		result.Apply(analysis)
+	}
+	return ok
+}
+
+// LinkResult is the outcome of one link check.
+type LinkResult struct {
+	Link    models.LinkInfo
+	Status  string // "OK", "Status: 404 Not Found", "Error: …", "Blocked: …" or an expired certificate note
+	Broken  bool
+	Blocked bool // refused by the network policy, not broken
+}
+
+// Apply records r in analysis.
+func (r LinkResult) Apply(analysis *models.PageAnalysis) {
+	switch {
+	case r.Blocked:
+		analysis.BlockedLinks++
+	case r.Broken:
+		analysis.BrokenLinks++
+	}
+	if analysis.LinksStatus != nil {
+		analysis.LinksStatus[r.Link.URL] = r.Status
+	}
+}
+
+// Result checks link, bounded by ctx as well as by the checker's timeout. It is safe for
+// concurrent use. The result is not ok for links other than http and https ones, and when
+// ctx ends before the check does: the link was not checked rather than found broken.
+func (lc LinkChecker) Result(ctx context.Context, link models.LinkInfo) (LinkResult, bool) {
+	result := LinkResult{Link: link}
 	if !strings.HasPrefix(link.URL, "http://") && !strings.HasPrefix(link.URL, "https://") {
-		return
+		return result, false
+	}
+	if ctx.Err() != nil {
+		return result, false
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second) // keep timeout for each request
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, lc.Timeout) // keep timeout for each request
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, link.URL, nil)
 	if err != nil {
 		slog.Debug("error creating request", "url", link.URL, "error", err)
-		analysis.Mutex.Lock()
-		analysis.BrokenLinks++
-		if analysis.LinksStatus != nil {
-			analysis.LinksStatus[link.URL] = "Error: " + err.Error()
-			analysis.Mutex.Unlock()
-		}
-		return
+		result.Status, result.Broken = "Error: "+err.Error(), true
+		return result, true
 	}
 
-	req.Header.Set("User-Agent", "WebAnalyzer/1.0")
+	req.Header.Set("User-Agent", lc.UserAgent)
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// Allow up to 10 redirects
-			if len(via) >= 10 {
-				return http.ErrUseLastResponse
-			}
-			return nil
-		},
+	client := NewHTTPClient(lc.Timeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= lc.MaxRedirects {
+			return http.ErrUseLastResponse
+		}
+		return nil
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
+		if parent.Err() != nil {
+			return result, false
+		}
 		slog.Debug("error checking link", "url", link.URL, "error", err)
 
-		analysis.Mutex.Lock()
-		analysis.BrokenLinks++
-		if analysis.LinksStatus != nil {
-			analysis.LinksStatus[link.URL] = "Error: " + err.Error()
-			analysis.Mutex.Unlock()
+		if errors.Is(err, netguard.ErrBlocked) {
+			result.Status, result.Blocked = "Blocked: "+err.Error(), true
+			return result, true
 		}
-		return
+
+		result.Status, result.Broken = "Error: "+err.Error(), true
+		if notAfter, expired := IsCertificateExpiredError(err); expired {
+			result.Status = expiredCertStatus(notAfter)
+		}
+		return result, true
 	}
 	defer resp.Body.Close()
 
-	analysis.Mutex.Lock()
 	if resp.StatusCode >= 400 {
-		analysis.BrokenLinks++
-		if analysis.LinksStatus != nil {
-			analysis.LinksStatus[link.URL] = "Status: " + resp.Status
-		}
-	} else if analysis.LinksStatus != nil {
-		analysis.LinksStatus[link.URL] = "OK"
+		result.Status, result.Broken = "Status: "+resp.Status, true
+	} else {
+		result.Status = "OK"
 	}
-	analysis.Mutex.Unlock()
+	return result, true
 }
 
 func IsExternalLink(linkURL, baseURL string) bool {
@@ -1,79 +1,177 @@
 package utils
 
 import (
-	"bufio"
-	"compress/gzip"
-	"fmt"
-	"io"
-	"net/http"
-	"regexp"
 	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Document modes a browser selects from the DOCTYPE, see
+// https://html.spec.whatwg.org/multipage/parsing.html#the-initial-insertion-mode
+const (
+	DocumentModeStandards     = "standards"
+	DocumentModeLimitedQuirks = "limited-quirks"
+	DocumentModeQuirks        = "quirks"
 )
 
-func DetectHTMLVersion(resp *http.Response) string {
+// DetectHTMLVersion determines the HTML version from the DOCTYPE of a parsed document.
+// The Content-Type is used as a fallback for XHTML documents served without a DOCTYPE.
+func DetectHTMLVersion(doc *html.Node, contentType string) string {
+	doctype := findDoctype(doc)
+	if doctype == nil {
+		if strings.Contains(contentType, "application/xhtml+xml") {
+			return "XHTML (Content-Type based detection)"
+		}
+		if doc != nil && doc.FirstChild != nil {
+			return "HTML (No DOCTYPE)"
+		}
+		return "Unknown"
+	}
+
+	publicID, hasPublic := doctypeID(doctype, "public")
+	_, hasSystem := doctypeID(doctype, "system")
 
-	var reader io.Reader = resp.Body
+	if doctype.Data == "html" && !hasPublic && !hasSystem {
+		return "HTML5"
+	}
+
+	if version, ok := publicIDVersions[strings.ToLower(publicID)]; ok {
+		return version
+	}
+
+	if strings.Contains(contentType, "application/xhtml+xml") {
+		return "XHTML (Content-Type based detection)"
+	}
+	return "HTML (Non-standard DOCTYPE)"
+}
+
+// DetectDocumentMode reports whether a browser would render the document in
+// standards, limited-quirks or quirks mode based on its DOCTYPE.
+func DetectDocumentMode(doc *html.Node) string {
+	doctype := findDoctype(doc)
+	if doctype == nil || doctype.Data != "html" {
+		return DocumentModeQuirks
+	}
 
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		gzipReader, err := gzip.NewReader(resp.Body)
-		if err == nil {
-			defer gzipReader.Close()
-			reader = gzipReader
-		} else {
-			return "Unknown (gzip decoding error)"
+	publicID, _ := doctypeID(doctype, "public")
+	systemID, hasSystem := doctypeID(doctype, "system")
+	publicID = strings.ToLower(publicID)
+	systemID = strings.ToLower(systemID)
+
+	switch publicID {
+	case "-//w3o//dtd w3 html strict 3.0//en//", "-/w3d/dtd html 4.0 transitional/en", "html":
+		return DocumentModeQuirks
+	}
+	if systemID == "http://www.ibm.com/data/dtd/v11/ibmxhtml1-transitional.dtd" {
+		return DocumentModeQuirks
+	}
+	for _, prefix := range quirksPublicIDPrefixes {
+		if strings.HasPrefix(publicID, prefix) {
+			return DocumentModeQuirks
 		}
 	}
 
-	bufReader := bufio.NewReaderSize(reader, 4096)
-	documentTypeBuffer, err := bufReader.Peek(4096)
-	if err != nil && err != io.EOF {
-		return "Unknown (detection error)"
+	isHTML401Loose := strings.HasPrefix(publicID, "-//w3c//dtd html 4.01 frameset//") ||
+		strings.HasPrefix(publicID, "-//w3c//dtd html 4.01 transitional//")
+	if isHTML401Loose && !hasSystem {
+		return DocumentModeQuirks
 	}
 
-	snippet := string(documentTypeBuffer)
-	contentType := resp.Header.Get("Content-Type")
-	isXHTML := strings.Contains(contentType, "application/xhtml+xml")
+	if isHTML401Loose ||
+		strings.HasPrefix(publicID, "-//w3c//dtd xhtml 1.0 frameset//") ||
+		strings.HasPrefix(publicID, "-//w3c//dtd xhtml 1.0 transitional//") {
+		return DocumentModeLimitedQuirks
+	}
 
-	// Debug print
-	fmt.Println("HTML Snippet Start:\n", snippet[:min(500, len(snippet))])
-	fmt.Println("Content-Type:", contentType)
+	return DocumentModeStandards
+}
 
-	switch {
-	case html5Regex.MatchString(snippet):
-		return "HTML5"
-	case html401StrictRegex.MatchString(snippet):
-		return "HTML 4.01 Strict"
-	case html401TransRegex.MatchString(snippet):
-		return "HTML 4.01 Transitional"
-	case html401FrameRegex.MatchString(snippet):
-		return "HTML 4.01 Frameset"
-	case xhtml10StrictRegex.MatchString(snippet):
-		return "XHTML 1.0 Strict"
-	case xhtml10TransRegex.MatchString(snippet):
-		return "XHTML 1.0 Transitional"
-	case xhtml10FrameRegex.MatchString(snippet):
-		return "XHTML 1.0 Frameset"
-	case xhtml11Regex.MatchString(snippet):
-		return "XHTML 1.1"
-	case isXHTML:
-		return "XHTML (Content-Type based detection)"
-	case strings.Contains(snippet, "<!DOCTYPE html"):
-		return "HTML (Non-standard DOCTYPE)"
-	default:
-		if strings.Contains(strings.ToLower(snippet), "<html") {
-			return "HTML (No DOCTYPE)"
+func findDoctype(doc *html.Node) *html.Node {
+	if doc == nil {
+		return nil
+	}
+	for c := doc.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.DoctypeNode {
+			return c
 		}
-		return "Unknown"
 	}
+	return nil
 }
 
-var (
-	html5Regex         = regexp.MustCompile(`(?i)<!DOCTYPE\s+html\s*>`)
-	html401StrictRegex = regexp.MustCompile(`(?i)<!DOCTYPE\s+HTML\s+PUBLIC\s+"-//W3C//DTD HTML 4\.01//EN"`)
-	html401TransRegex  = regexp.MustCompile(`(?i)<!DOCTYPE\s+HTML\s+PUBLIC\s+"-//W3C//DTD HTML 4\.01 Transitional//EN"`)
-	html401FrameRegex  = regexp.MustCompile(`(?i)<!DOCTYPE\s+HTML\s+PUBLIC\s+"-//W3C//DTD HTML 4\.01 Frameset//EN"`)
-	xhtml10StrictRegex = regexp.MustCompile(`(?i)<!DOCTYPE\s+html\s+PUBLIC\s+"-//W3C//DTD XHTML 1\.0 Strict//EN"`)
-	xhtml10TransRegex  = regexp.MustCompile(`(?i)<!DOCTYPE\s+html\s+PUBLIC\s+"-//W3C//DTD XHTML 1\.0 Transitional//EN"`)
-	xhtml10FrameRegex  = regexp.MustCompile(`(?i)<!DOCTYPE\s+html\s+PUBLIC\s+"-//W3C//DTD XHTML 1\.0 Frameset//EN"`)
-	xhtml11Regex       = regexp.MustCompile(`(?i)<!DOCTYPE\s+html\s+PUBLIC\s+"-//W3C//DTD XHTML 1\.1//EN"`)
-)
+func doctypeID(doctype *html.Node, key string) (string, bool) {
+	for _, attr := range doctype.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+var publicIDVersions = map[string]string{
+	"-//w3c//dtd html 4.01//en":              "HTML 4.01 Strict",
+	"-//w3c//dtd html 4.01 transitional//en": "HTML 4.01 Transitional",
+	"-//w3c//dtd html 4.01 frameset//en":     "HTML 4.01 Frameset",
+	"-//w3c//dtd xhtml 1.0 strict//en":       "XHTML 1.0 Strict",
+	"-//w3c//dtd xhtml 1.0 transitional//en": "XHTML 1.0 Transitional",
+	"-//w3c//dtd xhtml 1.0 frameset//en":     "XHTML 1.0 Frameset",
+	"-//w3c//dtd xhtml 1.1//en":              "XHTML 1.1",
+}
+
+// quirksPublicIDPrefixes are the lower-cased public identifiers that trigger quirks mode.
+var quirksPublicIDPrefixes = []string{
+	"+//silmaril//dtd html pro v0r11 19970101//",
+	"-//as//dtd html 3.0 aswedit + extensions//",
+	"-//advasoft ltd//dtd html 3.0 aswedit + extensions//",
+	"-//ietf//dtd html 2.0 level 1//",
+	"-//ietf//dtd html 2.0 level 2//",
+	"-//ietf//dtd html 2.0 strict level 1//",
+	"-//ietf//dtd html 2.0 strict level 2//",
+	"-//ietf//dtd html 2.0 strict//",
+	"-//ietf//dtd html 2.0//",
+	"-//ietf//dtd html 2.1e//",
+	"-//ietf//dtd html 3.0//",
+	"-//ietf//dtd html 3.2 final//",
+	"-//ietf//dtd html 3.2//",
+	"-//ietf//dtd html 3//",
+	"-//ietf//dtd html level 0//",
+	"-//ietf//dtd html level 1//",
+	"-//ietf//dtd html level 2//",
+	"-//ietf//dtd html level 3//",
+	"-//ietf//dtd html strict level 0//",
+	"-//ietf//dtd html strict level 1//",
+	"-//ietf//dtd html strict level 2//",
+	"-//ietf//dtd html strict level 3//",
+	"-//ietf//dtd html strict//",
+	"-//ietf//dtd html//",
+	"-//metrius//dtd metrius presentational//",
+	"-//microsoft//dtd internet explorer 2.0 html strict//",
+	"-//microsoft//dtd internet explorer 2.0 html//",
+	"-//microsoft//dtd internet explorer 2.0 tables//",
+	"-//microsoft//dtd internet explorer 3.0 html strict//",
+	"-//microsoft//dtd internet explorer 3.0 html//",
+	"-//microsoft//dtd internet explorer 3.0 tables//",
+	"-//netscape comm. corp.//dtd html//",
+	"-//netscape comm. corp.//dtd strict html//",
+	"-//o'reilly and associates//dtd html 2.0//",
+	"-//o'reilly and associates//dtd html extended 1.0//",
+	"-//o'reilly and associates//dtd html extended relaxed 1.0//",
+	"-//sq//dtd html 2.0 hotmetal + extensions//",
+	"-//softquad software//dtd hotmetal pro 6.0::19990601::extensions to html 4.0//",
+	"-//softquad//dtd hotmetal pro 4.0::19971010::extensions to html 4.0//",
+	"-//spyglass//dtd html 2.0 extended//",
+	"-//sun microsystems corp.//dtd hotjava html//",
+	"-//sun microsystems corp.//dtd hotjava strict html//",
+	"-//w3c//dtd html 3 1995-03-24//",
+	"-//w3c//dtd html 3.2 draft//",
+	"-//w3c//dtd html 3.2 final//",
+	"-//w3c//dtd html 3.2//",
+	"-//w3c//dtd html 3.2s draft//",
+	"-//w3c//dtd html 4.0 frameset//",
+	"-//w3c//dtd html 4.0 transitional//",
+	"-//w3c//dtd html experimental 19960712//",
+	"-//w3c//dtd html experimental 970421//",
+	"-//w3c//dtd w3 html//",
+	"-//w3o//dtd w3 html 3.0//",
+	"-//webtechs//dtd mozilla html 2.0//",
+	"-//webtechs//dtd mozilla html//",
+}
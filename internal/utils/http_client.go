@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"web-analyzer/internal/netguard"
+	"web-analyzer/pkg/metrics"
+)
+
+// TransportSettings tunes the transport shared by every page fetch, link check and
+// webhook delivery. Zero timeouts mean no limit beyond the client timeout.
+type TransportSettings struct {
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	IdleConnTimeout       time.Duration
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	DisableHTTP2          bool
+}
+
+// DefaultTransportSettings keeps enough idle connections per host for every link
+// worker checking links on the analyzed site.
+func DefaultTransportSettings() TransportSettings {
+	return TransportSettings{
+		MaxIdleConns:        200,
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     90 * time.Second,
+		DialTimeout:         10 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+var (
+	networkGuard atomic.Pointer[netguard.Guard]
+
+	// transportMu serializes rebuilds of the shared transport; reads go through the
+	// atomic pointer.
+	transportMu       sync.Mutex
+	transportSettings = DefaultTransportSettings()
+	sharedTransport   atomic.Pointer[tracedTransport]
+)
+
+func init() {
+	networkGuard.Store(netguard.Default())
+	rebuildTransport()
+}
+
+// SetNetworkGuard replaces the policy applied to every outbound page fetch and link check.
+// Pooled connections opened under the previous policy are dropped.
+func SetNetworkGuard(g *netguard.Guard) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	networkGuard.Store(g)
+	rebuildTransport()
+}
+
+// NetworkGuard returns the policy currently applied to outbound requests.
+func NetworkGuard() *netguard.Guard {
+	return networkGuard.Load()
+}
+
+// SetTransportSettings replaces the shared transport with one tuned by s.
+func SetTransportSettings(s TransportSettings) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	transportSettings = s
+	rebuildTransport()
+}
+
+// CurrentTransportSettings returns the settings of the shared transport.
+func CurrentTransportSettings() TransportSettings {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	return transportSettings
+}
+
+// NewHTTPClient returns a client on the shared transport, so connections to a host are
+// reused across the link checks of one page and across analyses. Its connections are
+// checked against the network guard, including one replaced after the client was
+// created. Proxies from the environment are ignored, as they would hide the real
+// destination from the guard.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: currentTransport{},
+	}
+}
+
+// currentTransport sends each request through the shared transport in place when it
+// starts, so long-lived clients follow guard and settings changes.
+type currentTransport struct{}
+
+func (currentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return sharedTransport.Load().RoundTrip(req)
+}
+
+func (currentTransport) CloseIdleConnections() {
+	sharedTransport.Load().CloseIdleConnections()
+}
+
+// NewUnverifiedHTTPClient returns a client that accepts any server certificate, used to
+// inspect a page whose certificate failed verification. Its connections are checked
+// against the network guard but not pooled.
+func NewUnverifiedHTTPClient(timeout time.Duration) *http.Client {
+	transport := newTransport(CurrentTransportSettings())
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	transport.DisableKeepAlives = true
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// rebuildTransport swaps in a transport for the current guard and settings and closes
+// the idle connections of the old one. transportMu must be held, except during init.
+func rebuildTransport() {
+	if old := sharedTransport.Swap(&tracedTransport{newTransport(transportSettings)}); old != nil {
+		old.CloseIdleConnections()
+	}
+}
+
+func newTransport(s TransportSettings) *http.Transport {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(!s.DisableHTTP2)
+
+	return &http.Transport{
+		DialContext:           NetworkGuard().Dialer(s.DialTimeout).DialContext,
+		MaxIdleConns:          s.MaxIdleConns,
+		MaxIdleConnsPerHost:   s.MaxIdleConnsPerHost,
+		IdleConnTimeout:       s.IdleConnTimeout,
+		TLSHandshakeTimeout:   s.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: s.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		Protocols:             protocols,
+	}
+}
+
+// tracedTransport counts whether each request went out on a pooled connection.
+type tracedTransport struct {
+	*http.Transport
+}
+
+func (t *tracedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reused atomic.Bool
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused.Store(info.Reused) },
+	}
+	resp, err := t.Transport.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	if err != nil {
+		return nil, err
+	}
+
+	connection := "new"
+	if reused.Load() {
+		connection = "reused"
+	}
+	metrics.OutboundRequests.WithLabelValues(connection, resp.Proto).Inc()
+	return resp, nil
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/net/html"
+
+	"web-analyzer/internal/models"
+)
+
+// LimitedReader reads at most Limit bytes from Reader and then reports io.EOF,
+// recording in Exceeded whether the underlying stream had more data. A Limit
+// of zero or less disables the limit.
+type LimitedReader struct {
+	Reader   io.Reader
+	Limit    int64
+	Exceeded bool
+	read     int64
+}
+
+func (l *LimitedReader) Read(p []byte) (int, error) {
+	if l.Limit <= 0 {
+		return l.Reader.Read(p)
+	}
+
+	remaining := l.Limit - l.read
+	if remaining <= 0 {
+		if !l.Exceeded {
+			var probe [1]byte
+			if _, err := io.ReadFull(l.Reader, probe[:]); err == nil {
+				l.Exceeded = true
+			}
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.Reader.Read(p)
+	l.read += int64(n)
+	return n, err
+}
+
+// ParseHTML parses r like html.Parse, but stops reading once the document has more
+// element nodes or deeper nesting than limits allow, so an oversized document is never
+// built in memory. reason is non-empty when the input was cut short.
+func ParseHTML(r io.Reader, limits models.ParseLimits) (doc *html.Node, reason string, err error) {
+	if limits.MaxNodes <= 0 && limits.MaxDepth <= 0 {
+		doc, err = html.Parse(r)
+		return doc, "", err
+	}
+	counted := &tokenLimitedReader{tokenizer: html.NewTokenizer(r), limits: limits}
+	doc, err = html.Parse(counted)
+	return doc, counted.reason, err
+}
+
+// voidElements never have content, so they do not open a nesting level.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// impliedEndTags are implicitly closed by another start tag of the same name, so
+// "<p><p>" or an unclosed list of "<li>" stays at one level.
+var impliedEndTags = map[string]bool{
+	"p": true, "li": true, "dt": true, "dd": true, "option": true, "optgroup": true,
+	"tr": true, "td": true, "th": true, "rb": true, "rt": true, "rp": true,
+}
+
+// tokenLimitedReader passes the raw bytes of its input through token by token, counting
+// start tags and tracking the open elements. It reports io.EOF at the first token that
+// would exceed the limits. The nesting it tracks approximates the parser's tree, which
+// the traversal limits then enforce exactly.
+type tokenLimitedReader struct {
+	tokenizer *html.Tokenizer
+	limits    models.ParseLimits
+	pending   []byte
+	open      []string
+	nodes     int
+	reason    string
+	err       error
+}
+
+func (t *tokenLimitedReader) Read(p []byte) (int, error) {
+	for len(t.pending) == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+		t.next()
+	}
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+func (t *tokenLimitedReader) next() {
+	tokenType := t.tokenizer.Next()
+	if tokenType == html.ErrorToken {
+		t.err = t.tokenizer.Err()
+		return
+	}
+
+	switch tokenType {
+	case html.StartTagToken, html.SelfClosingTagToken:
+		name, _ := t.tokenizer.TagName()
+		tag := string(name)
+		t.nodes++
+		if t.limits.MaxNodes > 0 && t.nodes > t.limits.MaxNodes {
+			t.stop(fmt.Sprintf("document exceeded %d element nodes", t.limits.MaxNodes))
+			return
+		}
+		if tokenType == html.StartTagToken && !voidElements[tag] {
+			if impliedEndTags[tag] && len(t.open) > 0 && t.open[len(t.open)-1] == tag {
+				t.open = t.open[:len(t.open)-1]
+			}
+			t.open = append(t.open, tag)
+			if t.limits.MaxDepth > 0 && len(t.open) > t.limits.MaxDepth {
+				t.stop(fmt.Sprintf("document nesting exceeded depth %d", t.limits.MaxDepth))
+				return
+			}
+		}
+	case html.EndTagToken:
+		name, _ := t.tokenizer.TagName()
+		for i := len(t.open) - 1; i >= 0; i-- {
+			if t.open[i] == string(name) {
+				t.open = t.open[:i]
+				break
+			}
+		}
+	}
+	t.pending = append(t.pending[:0], t.tokenizer.Raw()...)
+}
+
+func (t *tokenLimitedReader) stop(reason string) {
+	t.reason = reason
+	t.err = io.EOF
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LinkFilter selects which links are checked. A nil *LinkFilter allows every link.
+type LinkFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewLinkFilter compiles the include and exclude regular expressions. It returns nil
+// when both lists are empty.
+func NewLinkFilter(include, exclude []string) (*LinkFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	f := &LinkFilter{}
+	var err error
+	if f.include, err = compilePatterns(include); err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	if f.exclude, err = compilePatterns(exclude); err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	return f, nil
+}
+
+// Allows reports whether link matches an include pattern (when there are any) and no exclude pattern.
+func (f *LinkFilter) Allows(link string) bool {
+	if f == nil {
+		return true
+	}
+	for _, re := range f.exclude {
+		if re.MatchString(link) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(link) {
+			return true
+		}
+	}
+	return false
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"web-analyzer/internal/models"
+)
+
+// mixedContentAttrs maps elements that load sub-resources to the attributes
+// holding their URLs and whether browsers treat them as active content.
+var mixedContentAttrs = map[string]struct {
+	attrs  []string
+	active bool
+}{
+	"script": {[]string{"src"}, true},
+	"iframe": {[]string{"src"}, true},
+	"frame":  {[]string{"src"}, true},
+	"object": {[]string{"data"}, true},
+	"embed":  {[]string{"src"}, true},
+	"img":    {[]string{"src", "srcset"}, false},
+	"audio":  {[]string{"src"}, false},
+	"video":  {[]string{"src", "poster"}, false},
+	"source": {[]string{"src", "srcset"}, false},
+	"track":  {[]string{"src"}, false},
+}
+
+// recordMixedContent flags http:// sub-resources and link downgrades on an HTTPS page.
+func recordMixedContent(n *html.Node, analysis *models.PageAnalysis, baseURL string) {
+	if !isHTTPS(baseURL) {
+		return
+	}
+
+	switch n.Data {
+	case "a":
+		if href := attrValue(n, "href"); isInsecureURL(href) {
+			addMixedContent(analysis, n.Data, "href", href, models.MixedContentLinkDowngrade)
+		}
+		return
+	case "link":
+		href := attrValue(n, "href")
+		if !isInsecureURL(href) {
+			return
+		}
+		rel := strings.Fields(strings.ToLower(attrValue(n, "rel")))
+		switch {
+		case containsAny(rel, "preload"):
+			// Preloads are as blockable as the request they stand in for.
+			switch strings.ToLower(attrValue(n, "as")) {
+			case "image", "audio", "video":
+				addMixedContent(analysis, n.Data, "href", href, models.MixedContentPassive)
+			default:
+				addMixedContent(analysis, n.Data, "href", href, models.MixedContentActive)
+			}
+		case containsAny(rel, "stylesheet", "import", "modulepreload"):
+			addMixedContent(analysis, n.Data, "href", href, models.MixedContentActive)
+		case containsAny(rel, "icon", "apple-touch-icon"):
+			addMixedContent(analysis, n.Data, "href", href, models.MixedContentPassive)
+		}
+		return
+	}
+
+	spec, ok := mixedContentAttrs[n.Data]
+	if !ok {
+		return
+	}
+
+	contentType := models.MixedContentPassive
+	if spec.active {
+		contentType = models.MixedContentActive
+	}
+
+	for _, key := range spec.attrs {
+		val := attrValue(n, key)
+		if key == "srcset" {
+			for _, candidate := range srcsetURLs(val) {
+				if isInsecureURL(candidate) {
+					addMixedContent(analysis, n.Data, key, candidate, contentType)
+				}
+			}
+			continue
+		}
+		if isInsecureURL(val) {
+			addMixedContent(analysis, n.Data, key, val, contentType)
+		}
+	}
+}
+
+func addMixedContent(analysis *models.PageAnalysis, element, attr, rawURL, contentType string) {
+	switch contentType {
+	case models.MixedContentActive:
+		analysis.MixedContent.Active++
+	case models.MixedContentPassive:
+		analysis.MixedContent.Passive++
+	case models.MixedContentLinkDowngrade:
+		analysis.MixedContent.DowngradeLinks++
+	}
+
+	analysis.MixedContent.Items = append(analysis.MixedContent.Items, models.MixedContentItem{
+		Element:   element,
+		Attribute: attr,
+		URL:       rawURL,
+		Type:      contentType,
+	})
+}
+
+func attrValue(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
+
+// srcsetURLs extracts the candidate URLs from a srcset attribute ("a.png 1x, b.png 2x").
+func srcsetURLs(srcset string) []string {
+	var urls []string
+	for _, candidate := range strings.Split(srcset, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return urls
+}
+
+func isHTTPS(rawURL string) bool {
+	return strings.HasPrefix(strings.ToLower(rawURL), "https://")
+}
+
+func isInsecureURL(rawURL string) bool {
+	return strings.HasPrefix(strings.ToLower(rawURL), "http://")
+}
+
+func containsAny(values []string, targets ...string) bool {
+	for _, v := range values {
+		for _, t := range targets {
+			if v == t {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// soft404Pattern matches the wording of typical "page not found" pages.
+var soft404Pattern = regexp.MustCompile(`\b404\b|\bnot found\b|` +
+	`\b(?:page|file|resource) (?:does not|doesn't|no longer) exists?\b|` +
+	`\b(?:can ?not|can't|could not|couldn't) (?:be found|find (?:the|this|that) page)\b|` +
+	`\bno longer available\b`)
+
+// soft404MaxBodyText is the visible text length below which a body mentioning "not found"
+// counts as an error page; longer pages mentioning it are most likely articles.
+const soft404MaxBodyText = 1000
+
+// DetectSoft404 reports whether doc looks like an error page served with a success
+// status, and why: its title or first heading reads like "page not found", or its body
+// is short and does.
+func DetectSoft404(doc *html.Node) (bool, string) {
+	var title, heading string
+	var body strings.Builder
+	var walk func(n *html.Node, visible bool)
+	walk = func(n *html.Node, visible bool) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if title == "" {
+					title = textContent(n)
+				}
+				return
+			case "h1":
+				if heading == "" {
+					heading = textContent(n)
+				}
+			case "script", "style", "noscript", "template", "head":
+				visible = false
+			}
+		}
+		if n.Type == html.TextNode && visible && body.Len() <= soft404MaxBodyText {
+			if text := strings.TrimSpace(n.Data); text != "" {
+				body.WriteString(text)
+				body.WriteByte(' ')
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, visible)
+		}
+	}
+	walk(doc, true)
+
+	if match := soft404Pattern.FindString(strings.ToLower(title)); match != "" {
+		return true, `title contains "` + match + `"`
+	}
+	if match := soft404Pattern.FindString(strings.ToLower(heading)); match != "" {
+		return true, `heading contains "` + match + `"`
+	}
+	if text := strings.TrimSpace(body.String()); len(text) <= soft404MaxBodyText {
+		if match := soft404Pattern.FindString(strings.ToLower(text)); match != "" {
+			return true, `short page contains "` + match + `"`
+		}
+	}
+	return false, ""
+}
+
+// textContent returns the whitespace-normalized text below n.
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"web-analyzer/internal/models"
+)
+
+// ExpiredCertStatusPrefix marks a LinksStatus entry whose TLS certificate has expired.
+const ExpiredCertStatusPrefix = "Error: certificate expired"
+
+// InspectTLS summarises the negotiated TLS connection for host, checking the peer
+// chain for expiry, hostname mismatch and self-signed leaf certificates.
+func InspectTLS(state *tls.ConnectionState, host string) *models.TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	now := time.Now()
+	info := &models.TLSInfo{
+		Version:            tls.VersionName(state.Version),
+		CipherSuite:        tls.CipherSuiteName(state.CipherSuite),
+		NegotiatedProtocol: state.NegotiatedProtocol,
+		ServerName:         state.ServerName,
+		Certificates:       make([]models.CertificateInfo, 0, len(state.PeerCertificates)),
+	}
+
+	for _, cert := range state.PeerCertificates {
+		info.Certificates = append(info.Certificates, models.CertificateInfo{
+			Subject:       cert.Subject.String(),
+			Issuer:        cert.Issuer.String(),
+			SANs:          certificateSANs(cert),
+			NotBefore:     cert.NotBefore,
+			NotAfter:      cert.NotAfter,
+			DaysRemaining: daysUntil(cert.NotAfter, now),
+			IsCA:          cert.IsCA,
+		})
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		return info
+	}
+
+	leaf := state.PeerCertificates[0]
+	info.DaysRemaining = daysUntil(leaf.NotAfter, now)
+	info.Expired = now.After(leaf.NotAfter)
+	info.SelfSigned = isSelfSigned(leaf)
+	if host != "" {
+		info.HostnameMismatch = leaf.VerifyHostname(host) != nil
+	}
+
+	return info
+}
+
+// CertificateError returns the certificate verification failure wrapped in err, or nil
+// if err has another cause.
+func CertificateError(err error) error {
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &verifyErr) {
+		return verifyErr.Err
+	}
+	return nil
+}
+
+// IsCertificateExpiredError reports whether err is a TLS verification failure
+// caused by an expired certificate, returning the certificate's expiry time.
+func IsCertificateExpiredError(err error) (time.Time, bool) {
+	var invalidErr x509.CertificateInvalidError
+	if !errors.As(err, &invalidErr) || invalidErr.Reason != x509.Expired {
+		return time.Time{}, false
+	}
+	if invalidErr.Cert != nil {
+		return invalidErr.Cert.NotAfter, true
+	}
+	return time.Time{}, true
+}
+
+func expiredCertStatus(notAfter time.Time) string {
+	if notAfter.IsZero() {
+		return ExpiredCertStatusPrefix
+	}
+	return fmt.Sprintf("%s on %s", ExpiredCertStatusPrefix, notAfter.UTC().Format(time.DateOnly))
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	if cert.Subject.String() != cert.Issuer.String() {
+		return false
+	}
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+func daysUntil(t, now time.Time) int {
+	return int(t.Sub(now).Hours() / 24)
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"slices"
+	"sync"
+)
+
+// DeliveryLog keeps the most recent deliveries in memory, evicting the oldest once
+// capacity is reached. A nil log records nothing.
+type DeliveryLog struct {
+	mu       sync.RWMutex
+	capacity int
+	order    []string // delivery ids, oldest first
+	byID     map[string]Delivery
+}
+
+func NewDeliveryLog(capacity int) *DeliveryLog {
+	return &DeliveryLog{
+		capacity: max(capacity, 1),
+		byID:     make(map[string]Delivery),
+	}
+}
+
+// record stores a snapshot of d, replacing an earlier one with the same id.
+func (l *DeliveryLog) record(d *Delivery) {
+	if l == nil {
+		return
+	}
+
+	snapshot := *d
+	snapshot.Attempts = slices.Clone(d.Attempts)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.byID[d.ID]; !ok {
+		if len(l.order) == l.capacity {
+			delete(l.byID, l.order[0])
+			l.order = l.order[1:]
+		}
+		l.order = append(l.order, d.ID)
+	}
+	l.byID[d.ID] = snapshot
+}
+
+// Get returns the delivery with the given id. A keyID restricts it to that API key's
+// deliveries; an empty one, when the API is open, allows any.
+func (l *DeliveryLog) Get(keyID, id string) (Delivery, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	d, ok := l.byID[id]
+	if !ok || !visibleTo(d, keyID) {
+		return Delivery{}, false
+	}
+	return d, true
+}
+
+// List returns up to limit deliveries, newest first, optionally only those with the given
+// status. A keyID restricts them to that API key's deliveries, as in Get.
+func (l *DeliveryLog) List(keyID, status string, limit int) []Delivery {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	deliveries := make([]Delivery, 0, min(limit, len(l.order)))
+	for i := len(l.order) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		d := l.byID[l.order[i]]
+		if visibleTo(d, keyID) && (status == "" || d.Status == status) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries
+}
+
+func visibleTo(d Delivery, keyID string) bool {
+	return keyID == "" || d.KeyID == keyID
+}
@@ -0,0 +1,264 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"web-analyzer/internal/netguard"
+	"web-analyzer/internal/utils"
+	"web-analyzer/pkg/api"
+	"web-analyzer/pkg/metrics"
+)
+
+// Headers set on every delivery. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the shared secret.
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// ErrDeliveryFailed is returned when a payload could not be delivered within the retry policy.
+var ErrDeliveryFailed = errors.New("webhook delivery failed")
+
+// RetryPolicy controls redelivery after a failed attempt. The delay doubles after every
+// attempt, starting at InitialBackoff and capped at MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+}
+
+// Delivery states and records are defined in pkg/api, which clients import without
+// the server.
+const (
+	StatusPending   = api.StatusPending
+	StatusDelivered = api.StatusDelivered
+	StatusFailed    = api.StatusFailed
+)
+
+type (
+	Delivery = api.Delivery
+	Attempt  = api.DeliveryAttempt
+)
+
+// Sender posts signed JSON payloads. Connections go through the outbound network
+// guard, so webhooks cannot target internal addresses.
+type Sender struct {
+	client *http.Client
+	secret string
+	policy RetryPolicy
+	// Log records every delivery and its attempts. Optional.
+	Log *DeliveryLog
+	// Sleep waits between attempts; tests replace it to avoid real delays.
+	Sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewSender(secret string, policy RetryPolicy, timeout time.Duration) *Sender {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	client := utils.NewHTTPClient(timeout)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &Sender{client: client, secret: secret, policy: policy, Sleep: sleep}
+}
+
+// NewDelivery creates a pending delivery to url on behalf of the API key keyID, empty
+// when the API is open, so its id can be handed out before the payload is ready. It is
+// recorded in the log straight away.
+func (s *Sender) NewDelivery(url, keyID string) *Delivery {
+	delivery := &Delivery{
+		ID:        uuid.New().String(),
+		URL:       url,
+		KeyID:     keyID,
+		Status:    StatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.Log.record(delivery)
+	return delivery
+}
+
+// Send delivers payload as JSON to url, retrying network errors, 429 and 5xx responses.
+// The returned Delivery is complete even when err is non-nil.
+func (s *Sender) Send(ctx context.Context, url, keyID, event string, payload any) (*Delivery, error) {
+	delivery := s.NewDelivery(url, keyID)
+	return delivery, s.Deliver(ctx, delivery, event, payload)
+}
+
+// Deliver sends payload for a delivery created with NewDelivery, as Send does.
+func (s *Sender) Deliver(ctx context.Context, delivery *Delivery, event string, payload any) error {
+	delivery.Event = event
+	err := s.deliver(ctx, delivery, payload)
+
+	delivery.Status = StatusDelivered
+	if err != nil {
+		delivery.Status = StatusFailed
+	}
+	s.Log.record(delivery)
+	metrics.WebhookDeliveries.WithLabelValues(event, delivery.Status).Inc()
+	return err
+}
+
+func (s *Sender) deliver(ctx context.Context, delivery *Delivery, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var lastErr error
+	for n := 1; n <= s.policy.MaxAttempts; n++ {
+		if n > 1 {
+			if err := s.Sleep(ctx, s.backoff(n-1, lastErr)); err != nil {
+				return fmt.Errorf("%w: %w", ErrDeliveryFailed, err)
+			}
+		}
+
+		attempt, retry, err := s.attempt(ctx, delivery, body)
+		delivery.Attempts = append(delivery.Attempts, attempt)
+		if err == nil {
+			return nil
+		}
+		s.Log.record(delivery)
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+
+	return fmt.Errorf("%w after %d attempts: %w", ErrDeliveryFailed, len(delivery.Attempts), lastErr)
+}
+
+// statusError is a non-2xx response.
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("receiver responded %d %s", e.code, http.StatusText(e.code))
+}
+
+func (s *Sender) attempt(ctx context.Context, delivery *Delivery, body []byte) (Attempt, bool, error) {
+	start := time.Now()
+	attempt := Attempt{At: start.UTC()}
+	finish := func(retry bool, err error) (Attempt, bool, error) {
+		attempt.Duration = time.Since(start).String()
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		return attempt, retry, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return finish(false, err)
+	}
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", utils.DefaultUserAgent)
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(s.secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Only transient network failures are worth retrying.
+		return finish(ctx.Err() == nil && !errors.Is(err, netguard.ErrBlocked), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return finish(false, nil)
+	}
+
+	statusErr := &statusError{code: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		statusErr.retryAfter = time.Duration(seconds) * time.Second
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return finish(retry, statusErr)
+}
+
+// backoff is the delay before the attempt following the n-th failed one.
+func (s *Sender) backoff(n int, lastErr error) time.Duration {
+	delay := s.policy.InitialBackoff << (n - 1)
+	if delay <= 0 || (s.policy.MaxBackoff > 0 && delay > s.policy.MaxBackoff) {
+		delay = s.policy.MaxBackoff
+	}
+
+	var statusErr *statusError
+	if errors.As(lastErr, &statusErr) && statusErr.retryAfter > delay {
+		delay = statusErr.retryAfter
+		if s.policy.MaxBackoff > 0 {
+			delay = min(delay, s.policy.MaxBackoff)
+		}
+	}
+	return delay
+}
+
+// Sign returns the signature header value for body sent at timestamp (unix seconds).
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a received webhook. Receivers should reject
+// deliveries whose timestamp is further than tolerance from their clock.
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	timestamp := header.Get(HeaderTimestamp)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header", HeaderTimestamp)
+	}
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return fmt.Errorf("webhook timestamp outside the allowed window")
+	}
+
+	expected := Sign(secret, timestamp, body)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(header.Get(HeaderSignature))) != 1 {
+		return fmt.Errorf("invalid webhook signature")
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
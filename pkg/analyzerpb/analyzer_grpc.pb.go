@@ -0,0 +1,221 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: analyzer.proto
+
+// The gRPC API of the web analyzer. It shares the analysis core, and the request
+// validation, with POST /api/v1/analyze; see docs/api.md for the field semantics.
+//
+// Regenerate analyzer.pb.go and analyzer_grpc.pb.go after editing:
+//
+//   protoc --go_out=. --go_opt=paths=source_relative \
+//          --go-grpc_out=. --go-grpc_opt=paths=source_relative analyzer.proto
+
+package analyzerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AnalyzerService_Analyze_FullMethodName       = "/webanalyzer.v1.AnalyzerService/Analyze"
+	AnalyzerService_AnalyzeStream_FullMethodName = "/webanalyzer.v1.AnalyzerService/AnalyzeStream"
+	AnalyzerService_BatchAnalyze_FullMethodName  = "/webanalyzer.v1.AnalyzerService/BatchAnalyze"
+)
+
+// AnalyzerServiceClient is the client API for AnalyzerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AnalyzerServiceClient interface {
+	// Analyze analyzes one page. Results are served from the result cache when it is
+	// enabled and stored like REST results.
+	Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
+	// AnalyzeStream analyzes one page and streams each link check as it completes,
+	// followed by the full analysis as the last message. It always fetches the page.
+	AnalyzeStream(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AnalyzeStreamResponse], error)
+	// BatchAnalyze analyzes several pages concurrently. A failed page does not fail the
+	// batch: its result carries the error instead.
+	BatchAnalyze(ctx context.Context, in *BatchAnalyzeRequest, opts ...grpc.CallOption) (*BatchAnalyzeResponse, error)
+}
+
+type analyzerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnalyzerServiceClient(cc grpc.ClientConnInterface) AnalyzerServiceClient {
+	return &analyzerServiceClient{cc}
+}
+
+func (c *analyzerServiceClient) Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyzeResponse)
+	err := c.cc.Invoke(ctx, AnalyzerService_Analyze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyzerServiceClient) AnalyzeStream(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AnalyzeStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AnalyzerService_ServiceDesc.Streams[0], AnalyzerService_AnalyzeStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AnalyzeRequest, AnalyzeStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyzerService_AnalyzeStreamClient = grpc.ServerStreamingClient[AnalyzeStreamResponse]
+
+func (c *analyzerServiceClient) BatchAnalyze(ctx context.Context, in *BatchAnalyzeRequest, opts ...grpc.CallOption) (*BatchAnalyzeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchAnalyzeResponse)
+	err := c.cc.Invoke(ctx, AnalyzerService_BatchAnalyze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AnalyzerServiceServer is the server API for AnalyzerService service.
+// All implementations must embed UnimplementedAnalyzerServiceServer
+// for forward compatibility.
+type AnalyzerServiceServer interface {
+	// Analyze analyzes one page. Results are served from the result cache when it is
+	// enabled and stored like REST results.
+	Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error)
+	// AnalyzeStream analyzes one page and streams each link check as it completes,
+	// followed by the full analysis as the last message. It always fetches the page.
+	AnalyzeStream(*AnalyzeRequest, grpc.ServerStreamingServer[AnalyzeStreamResponse]) error
+	// BatchAnalyze analyzes several pages concurrently. A failed page does not fail the
+	// batch: its result carries the error instead.
+	BatchAnalyze(context.Context, *BatchAnalyzeRequest) (*BatchAnalyzeResponse, error)
+	mustEmbedUnimplementedAnalyzerServiceServer()
+}
+
+// UnimplementedAnalyzerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAnalyzerServiceServer struct{}
+
+func (UnimplementedAnalyzerServiceServer) Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Analyze not implemented")
+}
+func (UnimplementedAnalyzerServiceServer) AnalyzeStream(*AnalyzeRequest, grpc.ServerStreamingServer[AnalyzeStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AnalyzeStream not implemented")
+}
+func (UnimplementedAnalyzerServiceServer) BatchAnalyze(context.Context, *BatchAnalyzeRequest) (*BatchAnalyzeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchAnalyze not implemented")
+}
+func (UnimplementedAnalyzerServiceServer) mustEmbedUnimplementedAnalyzerServiceServer() {}
+func (UnimplementedAnalyzerServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeAnalyzerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnalyzerServiceServer will
+// result in compilation errors.
+type UnsafeAnalyzerServiceServer interface {
+	mustEmbedUnimplementedAnalyzerServiceServer()
+}
+
+func RegisterAnalyzerServiceServer(s grpc.ServiceRegistrar, srv AnalyzerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAnalyzerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AnalyzerService_ServiceDesc, srv)
+}
+
+func _AnalyzerService_Analyze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyzerServiceServer).Analyze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyzerService_Analyze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyzerServiceServer).Analyze(ctx, req.(*AnalyzeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyzerService_AnalyzeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AnalyzeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AnalyzerServiceServer).AnalyzeStream(m, &grpc.GenericServerStream[AnalyzeRequest, AnalyzeStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyzerService_AnalyzeStreamServer = grpc.ServerStreamingServer[AnalyzeStreamResponse]
+
+func _AnalyzerService_BatchAnalyze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAnalyzeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyzerServiceServer).BatchAnalyze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyzerService_BatchAnalyze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyzerServiceServer).BatchAnalyze(ctx, req.(*BatchAnalyzeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AnalyzerService_ServiceDesc is the grpc.ServiceDesc for AnalyzerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AnalyzerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "webanalyzer.v1.AnalyzerService",
+	HandlerType: (*AnalyzerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Analyze",
+			Handler:    _AnalyzerService_Analyze_Handler,
+		},
+		{
+			MethodName: "BatchAnalyze",
+			Handler:    _AnalyzerService_BatchAnalyze_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AnalyzeStream",
+			Handler:       _AnalyzerService_AnalyzeStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "analyzer.proto",
+}
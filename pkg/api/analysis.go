@@ -0,0 +1,166 @@
+// Package api holds the request and response types of the web analyzer HTTP API and
+// its request signing. It depends on the standard library only, so that clients can
+// import it without the server.
+package api
+
+import "time"
+
+type PageAnalysis struct {
+	ResultID         string            `json:"result_id,omitempty"` // set when the result was stored
+	StatusCode       int               `json:"status_code,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`         // response headers, repeated values joined with ", "
+	Soft404          bool              `json:"soft_404,omitempty"`        // a 200 response that reads like an error page
+	Soft404Reason    string            `json:"soft_404_reason,omitempty"` // what gave it away
+	HTMLVersion      string            `json:"html_version"`
+	DocumentMode     string            `json:"document_mode,omitempty"`
+	Title            string            `json:"title"`
+	Charset          string            `json:"charset,omitempty"`
+	Headings         map[string]int    `json:"headings"`
+	InternalLinks    int               `json:"internal_links"`
+	ExternalLinks    int               `json:"external_links"`
+	BrokenLinks      int               `json:"broken_links"`
+	BlockedLinks     int               `json:"blocked_links,omitempty"` // links refused by the network policy
+	SkippedLinks     int               `json:"skipped_links,omitempty"` // links found but not checked, see AnalysisOptions
+	HasLoginForm     bool              `json:"has_login_form"`
+	PageSize         int64             `json:"page_size_bytes,omitempty"` // decoded size
+	CompressedSize   int64             `json:"compressed_size_bytes,omitempty"`
+	ContentEncoding  string            `json:"content_encoding,omitempty"`
+	LoadTime         int64             `json:"load_time_ms,omitempty"`
+	LinksStatus      map[string]string `json:"links_status,omitempty"`
+	AnalysisDuration string            `json:"analysis_duration,omitempty"`
+	MetaTags         map[string]string `json:"meta_tags,omitempty"`
+	TLS              *TLSInfo          `json:"tls,omitempty"`
+	ExpiredCertLinks []string          `json:"expired_cert_links,omitempty"`
+	MixedContent     MixedContentInfo  `json:"mixed_content"`
+	Truncated        bool              `json:"truncated"`
+	TruncatedReason  string            `json:"truncated_reason,omitempty"`
+	Cancelled        bool              `json:"cancelled"` // the analysis deadline passed before every link was checked
+}
+
+// Truncate marks the analysis as partial. Only the first limit hit is reported.
+func (p *PageAnalysis) Truncate(reason string) {
+	if p.Truncated {
+		return
+	}
+	p.Truncated = true
+	p.TruncatedReason = reason
+}
+
+// MixedContentInfo lists insecure http:// references found on an HTTPS page.
+// Active content (scripts, stylesheets, frames) is blocked by browsers, passive
+// content (images, media) is loaded with a warning.
+type MixedContentInfo struct {
+	Active         int                `json:"active"`
+	Passive        int                `json:"passive"`
+	DowngradeLinks int                `json:"downgrade_links"`
+	Items          []MixedContentItem `json:"items,omitempty"`
+}
+
+type MixedContentItem struct {
+	Element   string `json:"element"`
+	Attribute string `json:"attribute"`
+	URL       string `json:"url"`
+	Type      string `json:"type"` // "active", "passive" or "link_downgrade"
+}
+
+const (
+	MixedContentActive        = "active"
+	MixedContentPassive       = "passive"
+	MixedContentLinkDowngrade = "link_downgrade"
+)
+
+// TLSInfo describes the TLS connection used to fetch an HTTPS page.
+type TLSInfo struct {
+	Version            string            `json:"version"`
+	CipherSuite        string            `json:"cipher_suite"`
+	NegotiatedProtocol string            `json:"negotiated_protocol,omitempty"`
+	ServerName         string            `json:"server_name,omitempty"`
+	Certificates       []CertificateInfo `json:"certificates"`
+	Expired            bool              `json:"expired"`
+	DaysRemaining      int               `json:"days_remaining"`
+	HostnameMismatch   bool              `json:"hostname_mismatch"`
+	SelfSigned         bool              `json:"self_signed"`
+	VerificationError  string            `json:"verification_error,omitempty"`
+}
+
+// CertificateInfo is a summary of one certificate in the peer chain, leaf first.
+type CertificateInfo struct {
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	SANs          []string  `json:"sans,omitempty"`
+	NotBefore     time.Time `json:"not_before"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining int       `json:"days_remaining"`
+	IsCA          bool      `json:"is_ca"`
+}
+
+// AnalysisRecord is a stored analysis result.
+type AnalysisRecord struct {
+	ID        string        `json:"id"`
+	URL       string        `json:"url"`
+	RequestID string        `json:"request_id,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	Analysis  *PageAnalysis `json:"analysis"`
+}
+
+// HistoryResponse is one page of a URL's stored results, newest first.
+type HistoryResponse struct {
+	URL     string            `json:"url"`
+	Total   int               `json:"total"`
+	Limit   int               `json:"limit"`
+	Offset  int               `json:"offset"`
+	Results []*AnalysisRecord `json:"results"`
+}
+
+// AnalyzeRequest is the body of POST /api/v1/analyze. GET requests take the same
+// fields as query parameters.
+type AnalyzeRequest struct {
+	URL              string   `json:"url"`
+	FlagExpiredCerts bool     `json:"flag_expired_certs,omitempty"`
+	CheckLinks       *bool    `json:"check_links,omitempty"` // defaults to true
+	Sections         []string `json:"sections,omitempty"`
+	LinkTimeout      string   `json:"link_timeout,omitempty"` // Go duration, e.g. "2s"
+	LinkConcurrency  int      `json:"link_concurrency,omitempty"`
+	MaxLinks         int      `json:"max_links,omitempty"`
+	Include          []string `json:"include,omitempty"`
+	Exclude          []string `json:"exclude,omitempty"`
+	UserAgent        string   `json:"user_agent,omitempty"`
+	AnyStatus        bool     `json:"any_status,omitempty"`   // analyze error pages too
+	CallbackURL      string   `json:"callback_url,omitempty"` // analyze asynchronously and POST the result here
+}
+
+// BatchAnalyzeRequest is the body of POST /api/v1/analyze/batch.
+type BatchAnalyzeRequest struct {
+	Requests []AnalyzeRequest `json:"requests"`
+}
+
+// BatchAnalyzeResponse holds one result per request, in request order.
+type BatchAnalyzeResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult is the outcome of one request in a batch. Exactly one of Analysis and
+// Error is set.
+type BatchResult struct {
+	URL      string         `json:"url"`
+	Analysis *PageAnalysis  `json:"analysis,omitempty"`
+	Error    *ErrorResponse `json:"error,omitempty"`
+}
+
+// CallbackAccepted is the 202 response to an analysis request with a callback_url.
+type CallbackAccepted struct {
+	RequestID   string `json:"request_id"`
+	DeliveryID  string `json:"delivery_id"`
+	CallbackURL string `json:"callback_url"`
+}
+
+// CallbackPayload is POSTed to the callback_url once an asynchronous analysis finished.
+// Exactly one of Analysis and Error is set.
+type CallbackPayload struct {
+	Event     string         `json:"event"`
+	RequestID string         `json:"request_id"`
+	URL       string         `json:"url"`
+	ResultID  string         `json:"result_id,omitempty"`
+	Analysis  *PageAnalysis  `json:"analysis,omitempty"`
+	Error     *ErrorResponse `json:"error,omitempty"`
+}
@@ -0,0 +1,77 @@
+package api
+
+import "time"
+
+// Report lists what changed between two analyses of the same page.
+type Report struct {
+	URL         string           `json:"url"`
+	From        Snapshot         `json:"from"`
+	To          Snapshot         `json:"to"`
+	Changed     bool             `json:"changed"`
+	Title       *ValueChange     `json:"title,omitempty"`
+	HTMLVersion *ValueChange     `json:"html_version,omitempty"`
+	LoginForm   *LoginFormChange `json:"login_form,omitempty"`
+	MetaTags    MetaTagChanges   `json:"meta_tags"`
+	Headings    []HeadingChange  `json:"headings,omitempty"`
+	Links       LinkChanges      `json:"links"`
+}
+
+// Snapshot identifies one side of the comparison.
+type Snapshot struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ValueChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type LoginFormChange struct {
+	Appeared    bool `json:"appeared"`
+	Disappeared bool `json:"disappeared"`
+}
+
+type MetaTagChanges struct {
+	Added   map[string]string      `json:"added,omitempty"`
+	Removed map[string]string      `json:"removed,omitempty"`
+	Changed map[string]ValueChange `json:"changed,omitempty"`
+}
+
+// HeadingChange is a change in the number of headings of one level.
+type HeadingChange struct {
+	Level string `json:"level"`
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+}
+
+// LinkChanges compares the checked links of both analyses. When either analysis did
+// not check every link on the page (Partial), a link it did not check is not reported
+// as added or removed: Added is only listed when From checked every link, Removed only
+// when To did, and status changes only cover links both checked.
+type LinkChanges struct {
+	Added         []string           `json:"added,omitempty"`
+	Removed       []string           `json:"removed,omitempty"`
+	NowBroken     []LinkStatusChange `json:"now_broken,omitempty"` // broken in To but not in From, including added links
+	Fixed         []LinkStatusChange `json:"fixed,omitempty"`      // broken in From, working in To
+	Counts        LinkCounts         `json:"counts"`
+	Partial       bool               `json:"partial,omitempty"`        // either analysis was truncated, cancelled or skipped links
+	PartialReason string             `json:"partial_reason,omitempty"` // which analysis, and why
+}
+
+type LinkStatusChange struct {
+	URL  string `json:"url"`
+	From string `json:"from,omitempty"` // empty for links that were not on the page before
+	To   string `json:"to"`
+}
+
+type LinkCounts struct {
+	Internal CountChange `json:"internal"`
+	External CountChange `json:"external"`
+	Broken   CountChange `json:"broken"`
+}
+
+type CountChange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
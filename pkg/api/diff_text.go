@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Text renders the report for people, e.g. in a release checklist or a chat message.
+func (r *Report) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Changes for %s\n", r.URL)
+	fmt.Fprintf(&b, "from %s (%s)\n", r.From.ID, r.From.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "to   %s (%s)\n", r.To.ID, r.To.CreatedAt.Format(time.RFC3339))
+
+	if !r.Changed {
+		b.WriteString("\nNo changes.\n")
+		return b.String()
+	}
+
+	if r.Title != nil {
+		fmt.Fprintf(&b, "\nTitle: %q -> %q\n", r.Title.From, r.Title.To)
+	}
+	if r.HTMLVersion != nil {
+		fmt.Fprintf(&b, "\nHTML version: %s -> %s\n", r.HTMLVersion.From, r.HTMLVersion.To)
+	}
+	if r.LoginForm != nil {
+		if r.LoginForm.Appeared {
+			b.WriteString("\nLogin form: appeared\n")
+		} else {
+			b.WriteString("\nLogin form: disappeared\n")
+		}
+	}
+
+	m := r.MetaTags
+	if len(m.Added)+len(m.Removed)+len(m.Changed) > 0 {
+		b.WriteString("\nMeta tags:\n")
+		for _, name := range slices.Sorted(maps.Keys(m.Added)) {
+			fmt.Fprintf(&b, "  + %s: %q\n", name, m.Added[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(m.Removed)) {
+			fmt.Fprintf(&b, "  - %s: %q\n", name, m.Removed[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(m.Changed)) {
+			fmt.Fprintf(&b, "  ~ %s: %q -> %q\n", name, m.Changed[name].From, m.Changed[name].To)
+		}
+	}
+
+	if len(r.Headings) > 0 {
+		b.WriteString("\nHeadings:\n")
+		for _, h := range r.Headings {
+			fmt.Fprintf(&b, "  %s: %d -> %d\n", h.Level, h.From, h.To)
+		}
+	}
+
+	l := r.Links
+	if len(l.Added)+len(l.Removed)+len(l.NowBroken)+len(l.Fixed) > 0 {
+		fmt.Fprintf(&b, "\nLinks (internal %d -> %d, external %d -> %d, broken %d -> %d):\n",
+			l.Counts.Internal.From, l.Counts.Internal.To,
+			l.Counts.External.From, l.Counts.External.To,
+			l.Counts.Broken.From, l.Counts.Broken.To)
+		for _, link := range l.NowBroken {
+			fmt.Fprintf(&b, "  ! %s now broken: %s\n", link.URL, link.To)
+		}
+		for _, link := range l.Fixed {
+			fmt.Fprintf(&b, "  * %s fixed: %s\n", link.URL, link.To)
+		}
+		for _, link := range l.Added {
+			fmt.Fprintf(&b, "  + %s\n", link)
+		}
+		for _, link := range l.Removed {
+			fmt.Fprintf(&b, "  - %s\n", link)
+		}
+	}
+	if l.Partial {
+		fmt.Fprintf(&b, "\nLinks compared partially: %s\n", l.PartialReason)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package api
+
+// ErrorResponse is the body of every error response. Code is stable and meant for
+// programs, Error and Details for people.
+type ErrorResponse struct {
+	Code    string       `json:"code"`
+	Error   string       `json:"error"`
+	Details string       `json:"details,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// Error codes of ErrorResponse.Code.
+const (
+	CodeInvalidInput        = "invalid_input"        // 400, a malformed request
+	CodeUpstreamUnreachable = "upstream_unreachable" // 422, the page's host could not be resolved or connected to
+	CodeUpstreamStatus      = "upstream_status"      // 502, the page answered with an error status
+	CodeUpstreamInvalid     = "upstream_invalid"     // 502, the page's body could not be decoded or parsed
+	CodeTimeout             = "timeout"              // 504, the page or the analysis took too long
+	CodeTooLarge            = "too_large"            // 413, the request body exceeds the size limit
+	CodeBlocked             = "blocked"              // 403, the destination is refused by the network policy
+	CodeCancelled           = "cancelled"            // 408, the client went away
+	CodeUnauthorized        = "unauthorized"         // 401
+	CodeNotFound            = "not_found"            // 404
+	CodeConflict            = "conflict"             // 409
+	CodeRateLimited         = "rate_limited"         // 429, per client or per key rate limit
+	CodeQuotaExceeded       = "quota_exceeded"       // 429, the key's daily quota is used up
+	CodeUnavailable         = "unavailable"          // 503, try again later
+	CodeNotImplemented      = "not_implemented"      // 501, the feature is disabled on this server
+	CodeInternal            = "internal"             // 500
+)
+
+// ErrorCodes lists every ErrorResponse code.
+var ErrorCodes = []string{
+	CodeInvalidInput, CodeUpstreamUnreachable, CodeUpstreamStatus, CodeUpstreamInvalid, CodeTimeout,
+	CodeTooLarge, CodeBlocked, CodeCancelled, CodeUnauthorized, CodeNotFound, CodeConflict,
+	CodeRateLimited, CodeQuotaExceeded, CodeUnavailable, CodeNotImplemented, CodeInternal,
+}
+
+// Problem is an RFC 7807 problem details object: the application/problem+json form of
+// an ErrorResponse, with Code, Fields and RequestID as extension members.
+type Problem struct {
+	Type      string       `json:"type"` // ProblemTypePrefix + Code
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"` // the request path
+	Code      string       `json:"code"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// ProblemTypePrefix prefixes the error code in Problem.Type.
+const ProblemTypePrefix = "urn:web-analyzer:problem:"
+
+// FieldError explains why one request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"strconv"
+	"time"
+)
+
+// EventAlert is the webhook event sent when a scheduled run raises alerts.
+const EventAlert = "schedule.alert"
+
+// Rule types. Threshold rules compare a numeric result field with Value; the change
+// rules compare a run with the job's previous successful run.
+const (
+	RuleThreshold            = "threshold"
+	RuleTitleMissing         = "title_missing"
+	RuleTitleChanged         = "title_changed"
+	RuleHTMLVersionChanged   = "html_version_changed"
+	RuleLoginFormAppeared    = "login_form_appeared"
+	RuleLoginFormDisappeared = "login_form_disappeared"
+	RuleNewBrokenLinks       = "new_broken_links"
+	RuleAnalysisFailed       = "analysis_failed"
+)
+
+// Rule raises an alert when a scheduled run matches it.
+type Rule struct {
+	Type  string  `json:"type"`
+	Field string  `json:"field,omitempty"` // threshold rules only
+	Op    string  `json:"op,omitempty"`    // threshold rules only: >, >=, <, <=, ==, !=
+	Value float64 `json:"value,omitempty"` // threshold rules only
+}
+
+// String describes the rule in alerts, e.g. "broken_links > 0".
+func (r Rule) String() string {
+	if r.Type == RuleThreshold {
+		return r.Field + " " + r.Op + " " + strconv.FormatFloat(r.Value, 'f', -1, 64)
+	}
+	return r.Type
+}
+
+// Alert is a rule that matched a run.
+type Alert struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// JobSpec describes a recurring analysis. Exactly one of Cron and Interval is set.
+type JobSpec struct {
+	URL        string `json:"url"`
+	Cron       string `json:"cron,omitempty"`     // five field cron expression or macro such as @hourly
+	Interval   string `json:"interval,omitempty"` // Go duration such as 15m, at least MinInterval
+	Timezone   string `json:"timezone,omitempty"` // IANA name the cron expression is evaluated in, UTC when empty
+	Rules      []Rule `json:"rules,omitempty"`
+	WebhookURL string `json:"webhook_url,omitempty"` // receives EventAlert payloads
+}
+
+// Job is a registered schedule and the state of its runs.
+type Job struct {
+	ID string `json:"id"`
+	JobSpec
+	CreatedAt    time.Time `json:"created_at"`
+	NextRun      time.Time `json:"next_run"`
+	LastRun      *Run      `json:"last_run,omitempty"`
+	LastResultID string    `json:"last_result_id,omitempty"` // newest stored result, compared with by change rules
+	KeyID        string    `json:"key_id,omitempty"`         // API key that created the job, charged for its runs
+}
+
+// Run is the outcome of one execution of a job.
+type Run struct {
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+	ResultID  string    `json:"result_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Alerts    []Alert   `json:"alerts,omitempty"`
+	Webhook   *Delivery `json:"webhook,omitempty"`
+}
+
+// AlertPayload is the body of an EventAlert webhook.
+type AlertPayload struct {
+	ScheduleID string        `json:"schedule_id"`
+	URL        string        `json:"url"`
+	RunAt      time.Time     `json:"run_at"`
+	ResultID   string        `json:"result_id,omitempty"`
+	Alerts     []Alert       `json:"alerts"`
+	Analysis   *PageAnalysis `json:"analysis,omitempty"`
+}
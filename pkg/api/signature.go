@@ -0,0 +1,62 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Credential headers. Requests carry either a static key in HeaderAPIKey (or
+// "Authorization: Bearer") or an HMAC signature made with SignRequest.
+const (
+	HeaderAPIKey    = "X-API-Key"
+	HeaderKeyID     = "X-Key-ID"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// MaxNonceLength bounds the HeaderNonce value the server accepts.
+const MaxNonceLength = 64
+
+// Signature computes the hex HMAC-SHA256 of
+// "METHOD\nREQUEST_URI\nTIMESTAMP\nNONCE\nhex(sha256(body))" with secret.
+func Signature(secret, method, requestURI, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + requestURI + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(bodyHash[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest adds the signing headers for keyID to req, with a fresh random nonce: the
+// server accepts each nonce of a key only once. The body, if any, must already be set.
+func SignRequest(req *http.Request, keyID, secret string, now time.Time) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(random)
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set(HeaderKeyID, keyID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, Signature(secret, req.Method, req.URL.RequestURI(), timestamp, nonce, body))
+	return nil
+}
@@ -0,0 +1,29 @@
+package api
+
+import "time"
+
+// Delivery states of Delivery.Status.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Delivery records the attempts made to deliver one payload.
+type Delivery struct {
+	ID        string            `json:"id"`
+	Event     string            `json:"event,omitempty"`
+	URL       string            `json:"url"`
+	KeyID     string            `json:"key_id,omitempty"` // API key of the schedule or request it reports on
+	Status    string            `json:"status"`
+	Attempts  []DeliveryAttempt `json:"attempts"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// DeliveryAttempt is one attempt to deliver a payload.
+type DeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Duration   string    `json:"duration"`
+}
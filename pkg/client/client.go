@@ -0,0 +1,333 @@
+// Package client is a typed Go client for the web analyzer HTTP API described by
+// /api/v1/openapi.json. Request and response types are the ones the server encodes.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"web-analyzer/pkg/api"
+)
+
+// API types, shared with the server through pkg/api.
+type (
+	AnalyzeRequest   = api.AnalyzeRequest
+	PageAnalysis     = api.PageAnalysis
+	CallbackAccepted = api.CallbackAccepted
+	CallbackPayload  = api.CallbackPayload
+	BatchResult      = api.BatchResult
+	AnalysisRecord   = api.AnalysisRecord
+	HistoryResponse  = api.HistoryResponse
+	ErrorResponse    = api.ErrorResponse
+	FieldError       = api.FieldError
+	DiffReport       = api.Report
+	JobSpec          = api.JobSpec
+	Job              = api.Job
+	Rule             = api.Rule
+	Run              = api.Run
+	AlertPayload     = api.AlertPayload
+	Delivery         = api.Delivery
+)
+
+// Options configures a Client. Set APIKey, or KeyID and Secret to sign requests, when
+// the server requires credentials.
+type Options struct {
+	HTTPClient *http.Client // http.DefaultClient when nil
+	APIKey     string       // sent as X-API-Key
+	KeyID      string       // signing key id, used with Secret
+	Secret     string       // signs each request with HMAC-SHA256 instead of sending a key
+	UserAgent  string
+}
+
+// Client calls one web analyzer server. It is safe for concurrent use.
+type Client struct {
+	baseURL *url.URL
+	opts    Options
+}
+
+// New returns a client for the server at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts Options) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+	if (opts.KeyID == "") != (opts.Secret == "") {
+		return nil, errors.New("KeyID and Secret must be set together")
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &Client{baseURL: u, opts: opts}, nil
+}
+
+// Error is returned for responses with an error status. Response holds the server's
+// error body when it sent one.
+type Error struct {
+	StatusCode int
+	RequestID  string
+	RetryAfter time.Duration // from the Retry-After header of 429 and 503 responses
+	Response   ErrorResponse
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("web analyzer: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Response.Error != "" {
+		msg += ": " + e.Response.Error
+	}
+	if e.Response.Details != "" {
+		msg += ": " + e.Response.Details
+	}
+	for _, f := range e.Response.Fields {
+		msg += fmt.Sprintf("; %s %s", f.Field, f.Message)
+	}
+	return msg
+}
+
+// IsNotFound reports whether err is a 404 from the server.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Analyze analyzes a page and waits for the result. Use AnalyzeAsync for requests
+// with a CallbackURL.
+func (c *Client) Analyze(ctx context.Context, req AnalyzeRequest) (*PageAnalysis, error) {
+	if req.CallbackURL != "" {
+		return nil, errors.New("requests with a CallbackURL must use AnalyzeAsync")
+	}
+	var result PageAnalysis
+	if err := c.do(ctx, http.MethodPost, "/api/v1/analyze", nil, req, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AnalyzeAsync starts an analysis whose result the server POSTs to req.CallbackURL.
+func (c *Client) AnalyzeAsync(ctx context.Context, req AnalyzeRequest) (*CallbackAccepted, error) {
+	if req.CallbackURL == "" {
+		return nil, errors.New("AnalyzeAsync needs a CallbackURL")
+	}
+	var accepted CallbackAccepted
+	if err := c.do(ctx, http.MethodPost, "/api/v1/analyze", nil, req, http.StatusAccepted, &accepted); err != nil {
+		return nil, err
+	}
+	return &accepted, nil
+}
+
+// Batch analyzes up to 20 pages in one request and returns one result per request,
+// in order. A failed analysis is reported in its BatchResult rather than as an error.
+func (c *Client) Batch(ctx context.Context, reqs []AnalyzeRequest) ([]BatchResult, error) {
+	var resp api.BatchAnalyzeResponse
+	body := api.BatchAnalyzeRequest{Requests: reqs}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/analyze/batch", nil, body, http.StatusOK, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// Export analyzes a page and returns the result in format: json, csv, html, junit or sarif.
+func (c *Client) Export(ctx context.Context, req AnalyzeRequest, format string) ([]byte, error) {
+	var out bytes.Buffer
+	query := url.Values{"format": {format}}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/analyze", query, req, http.StatusOK, &out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Result returns a stored result.
+func (c *Client) Result(ctx context.Context, id string) (*AnalysisRecord, error) {
+	var record AnalysisRecord
+	if err := c.do(ctx, http.MethodGet, "/api/v1/results/"+url.PathEscape(id), nil, nil, http.StatusOK, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ExportResult returns a stored result in format: json, csv, html, junit or sarif.
+func (c *Client) ExportResult(ctx context.Context, id, format string) ([]byte, error) {
+	var out bytes.Buffer
+	query := url.Values{"format": {format}}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/results/"+url.PathEscape(id), query, nil, http.StatusOK, &out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// History returns stored results of pageURL, newest first. Zero limit uses the server default.
+func (c *Client) History(ctx context.Context, pageURL string, limit, offset int) (*HistoryResponse, error) {
+	query := url.Values{"url": {pageURL}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+	var history HistoryResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/history", query, nil, http.StatusOK, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// Diff compares two stored results of the same URL.
+func (c *Client) Diff(ctx context.Context, fromID, toID string) (*DiffReport, error) {
+	var report DiffReport
+	query := url.Values{"from": {fromID}, "to": {toID}}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/diff", query, nil, http.StatusOK, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// CreateSchedule schedules a recurring analysis job.
+func (c *Client) CreateSchedule(ctx context.Context, spec JobSpec) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, http.MethodPost, "/api/v1/schedules", nil, spec, http.StatusCreated, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Schedules lists the scheduled jobs.
+func (c *Client) Schedules(ctx context.Context) ([]Job, error) {
+	var list struct {
+		Schedules []Job `json:"schedules"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/schedules", nil, nil, http.StatusOK, &list); err != nil {
+		return nil, err
+	}
+	return list.Schedules, nil
+}
+
+// Schedule returns one scheduled job.
+func (c *Client) Schedule(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	if err := c.do(ctx, http.MethodGet, "/api/v1/schedules/"+url.PathEscape(id), nil, nil, http.StatusOK, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// DeleteSchedule removes a scheduled job.
+func (c *Client) DeleteSchedule(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/schedules/"+url.PathEscape(id), nil, nil, http.StatusNoContent, nil)
+}
+
+// RunSchedule runs a job now and waits for the outcome.
+func (c *Client) RunSchedule(ctx context.Context, id string) (*Run, error) {
+	var run Run
+	if err := c.do(ctx, http.MethodPost, "/api/v1/schedules/"+url.PathEscape(id)+"/run", nil, nil, http.StatusOK, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// Deliveries lists recent webhook and callback deliveries, newest first. An empty status
+// lists all of them and zero limit uses the server default.
+func (c *Client) Deliveries(ctx context.Context, status string, limit int) ([]Delivery, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var list struct {
+		Deliveries []Delivery `json:"deliveries"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhooks/deliveries", query, nil, http.StatusOK, &list); err != nil {
+		return nil, err
+	}
+	return list.Deliveries, nil
+}
+
+// Delivery returns one delivery with its attempts.
+func (c *Client) Delivery(ctx context.Context, id string) (*Delivery, error) {
+	var delivery Delivery
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhooks/deliveries/"+url.PathEscape(id), nil, nil, http.StatusOK, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// do sends a request with body encoded as JSON, when not nil, and decodes a response with
+// status want into out: JSON for most types, the raw body for a *bytes.Buffer.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, want int, out any) error {
+	u := c.baseURL.JoinPath(path) // path is escaped
+	u.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if _, raw := out.(*bytes.Buffer); !raw {
+		req.Header.Set("Accept", "application/json")
+	}
+	if c.opts.UserAgent != "" {
+		req.Header.Set("User-Agent", c.opts.UserAgent)
+	}
+	if c.opts.APIKey != "" {
+		req.Header.Set(api.HeaderAPIKey, c.opts.APIKey)
+	}
+	if c.opts.Secret != "" {
+		if err := api.SignRequest(req, c.opts.KeyID, c.opts.Secret, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != want {
+		return responseError(resp)
+	}
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err := out.ReadFrom(resp.Body)
+		return err
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+		}
+		return nil
+	}
+}
+
+func responseError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	// A body that is not an error response, e.g. from a proxy, leaves Response empty.
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&apiErr.Response)
+	return apiErr
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -47,6 +49,99 @@ var (
 		[]string{"code"},
 	)
 
+	ContentEncodings = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "page_content_encodings_total",
+			Help:      "Content-Encoding of fetched pages",
+		},
+		[]string{"encoding"},
+	)
+
+	APIKeyRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "api_key_requests_total",
+			Help:      "API requests per key and authorization outcome",
+		},
+		[]string{"key_id", "outcome"},
+	)
+
+	APIKeyAnalyses = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "api_key_analyses_total",
+			Help:      "Analyses counted against each key's daily quota",
+		},
+		[]string{"key_id"},
+	)
+
+	RateLimited = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "rate_limited_requests_total",
+			Help:      "Requests rejected by the rate limiter per route group",
+		},
+		[]string{"group"},
+	)
+
+	CacheLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "result_cache_lookups_total",
+			Help:      "Analyze requests per result cache outcome (hit, revalidated, miss, bypass)",
+		},
+		[]string{"result"},
+	)
+
+	CoalescedRequests = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "web_analyzer",
+		Name:      "coalesced_requests_total",
+		Help:      "Analyze requests that joined an identical analysis already in flight",
+	})
+
+	ScheduledRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "scheduled_runs_total",
+			Help:      "Scheduled analyses per outcome",
+		},
+		[]string{"outcome"},
+	)
+
+	ScheduleAlerts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "web_analyzer",
+		Name:      "schedule_alerts_total",
+		Help:      "Alerts raised by scheduled analyses",
+	})
+
+	WebhookDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "webhook_deliveries_total",
+			Help:      "Webhook deliveries per event and outcome",
+		},
+		[]string{"event", "outcome"},
+	)
+
+	GRPCRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "grpc_requests_total",
+			Help:      "gRPC calls per method and status code",
+		},
+		[]string{"method", "code"},
+	)
+
+	OutboundRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "web_analyzer",
+			Name:      "outbound_requests_total",
+			Help:      "Page fetches and link checks per connection (reused, new) and protocol",
+		},
+		[]string{"connection", "protocol"},
+	)
+
 	ActiveRequests = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "web_analyzer",
 		Name:      "active_requests",
@@ -54,8 +149,12 @@ var (
 	})
 )
 
-func InitMetrics() { //  registers all metrics with Prometheus
-	prometheus.MustRegister(Requests)
+var initOnce sync.Once
+
+func InitMetrics() { //  registers all metrics with Prometheus, safe to call more than once
+	initOnce.Do(func() {
+		prometheus.MustRegister(Requests)
+	})
 }
 
 func IncrementActiveRequests() {
@@ -1,13 +1,34 @@
 package analysis_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/andybalholm/brotli"
 	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/japanese"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/netguard"
+	"web-analyzer/internal/utils"
+	"web-analyzer/pkg/metrics"
+	"web-analyzer/test/internal/testnet"
 )
 
 func TestHandleAnalyze(t *testing.T) {
@@ -52,3 +73,586 @@ func TestHandleAnalyze(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "invalid URL format")
 	})
 }
+
+func TestAnalyzePageCharset(t *testing.T) {
+	page := `<!DOCTYPE html><html><head><title>日本語のページ</title>` +
+		`<meta name="description" content="文字化けしない"></head><body><h1>見出し</h1></body></html>`
+
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(page)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=Shift_JIS")
+		_, _ = w.Write([]byte(encoded))
+	}))
+	defer ts.Close()
+
+	result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "shift_jis", result.Charset)
+	assert.Equal(t, "日本語のページ", result.Title)
+	assert.Equal(t, "文字化けしない", result.MetaTags["description"])
+}
+
+func TestAnalyzePageHTMLVersion(t *testing.T) {
+	page := `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN" "http://www.w3.org/TR/html4/loose.dtd">` +
+		`<html><head><title>Legacy</title></head><body></body></html>`
+
+	var compressed bytes.Buffer
+	bw := brotli.NewWriter(&compressed)
+	_, err := bw.Write([]byte(page))
+	require.NoError(t, err)
+	require.NoError(t, bw.Close())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write(compressed.Bytes())
+	}))
+	defer ts.Close()
+
+	result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "HTML 4.01 Transitional", result.HTMLVersion)
+	assert.Equal(t, "limited-quirks", result.DocumentMode)
+	assert.Equal(t, "Legacy", result.Title)
+}
+
+func TestAnalyzePageContentEncoding(t *testing.T) {
+	page := "<!DOCTYPE html><html><head><title>Compressed</title></head><body>" +
+		strings.Repeat("<p>repetitive content</p>", 200) + "</body></html>"
+
+	encoders := map[string]func(io.Writer) io.WriteCloser{
+		"gzip": func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"br":   func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		"zstd": func(w io.Writer) io.WriteCloser {
+			zw, _ := zstd.NewWriter(w)
+			return zw
+		},
+	}
+
+	for name, newWriter := range encoders {
+		t.Run(name, func(t *testing.T) {
+			var compressed bytes.Buffer
+			w := newWriter(&compressed)
+			_, err := w.Write([]byte(page))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Contains(t, r.Header.Get("Accept-Encoding"), name)
+				w.Header().Set("Content-Type", "text/html")
+				w.Header().Set("Content-Encoding", name)
+				_, _ = w.Write(compressed.Bytes())
+			}))
+			defer ts.Close()
+
+			result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+			require.NoError(t, err)
+
+			assert.Equal(t, name, result.ContentEncoding)
+			assert.Equal(t, "Compressed", result.Title)
+			assert.Equal(t, int64(len(page)), result.PageSize)
+			assert.Equal(t, int64(compressed.Len()), result.CompressedSize)
+		})
+	}
+
+	t.Run("identity", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(page))
+		}))
+		defer ts.Close()
+
+		result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+		require.NoError(t, err)
+
+		assert.Equal(t, "identity", result.ContentEncoding)
+		assert.Equal(t, int64(len(page)), result.PageSize)
+		assert.Equal(t, result.PageSize, result.CompressedSize)
+	})
+}
+
+func TestAnalyzePageUntrustedCertificate(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Self-signed</title></head><body></body></html>`))
+	}))
+	defer ts.Close()
+
+	t.Run("Self-signed", func(t *testing.T) {
+		result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "Self-signed", result.Title)
+		require.NotNil(t, result.TLS)
+		assert.True(t, result.TLS.SelfSigned)
+		assert.False(t, result.TLS.HostnameMismatch)
+		assert.False(t, result.TLS.Expired)
+		assert.Contains(t, result.TLS.VerificationError, "unknown authority")
+	})
+
+	t.Run("Hostname Mismatch", func(t *testing.T) {
+		result, err := analysis.AnalyzePage(context.Background(), strings.Replace(ts.URL, "127.0.0.1", "localhost", 1))
+		require.NoError(t, err)
+		require.NotNil(t, result.TLS)
+		assert.True(t, result.TLS.HostnameMismatch)
+	})
+
+	t.Run("TLS Section Not Requested", func(t *testing.T) {
+		_, err := analysis.AnalyzePageWithOptions(context.Background(), ts.URL, models.AnalysisOptions{
+			Sections: models.Sections{models.SectionTitle},
+		})
+		assert.ErrorIs(t, err, analysis.ErrUpstreamUnreachable)
+	})
+}
+
+func TestAnalyzePageMixedContentAfterRedirect(t *testing.T) {
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><head><script src="http://cdn.example.com/app.js"></script></head></html>`))
+	}))
+	defer secure.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(secure.URL+"/", http.StatusMovedPermanently))
+	defer redirect.Close()
+
+	result, err := analysis.AnalyzePage(context.Background(), redirect.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MixedContent.Active, "mixed content is judged on the page the redirect led to")
+}
+
+func TestHandleAnalyzeNetworkPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	guard, err := netguard.New([]string{"127.0.0.1"}, nil)
+	require.NoError(t, err)
+	utils.SetNetworkGuard(guard)
+	defer utils.SetNetworkGuard(testnet.LoopbackGuard())
+
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><title>internal</title></html>"))
+	}))
+	defer internal.Close()
+	internalURL := strings.Replace(internal.URL, "127.0.0.1", "127.0.0.2", 1)
+
+	redirector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internalURL, http.StatusFound)
+	}))
+	defer redirector.Close()
+
+	testCases := []struct {
+		name      string
+		targetURL string
+	}{
+		{"Metadata Endpoint", "http://169.254.169.254/latest/meta-data/"},
+		{"Loopback Outside Allow List", internalURL},
+		{"Redirect To Blocked Address", redirector.URL},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/?url="+tc.targetURL, nil)
+
+			analysis.HandleAnalyze(c)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), "Destination not allowed")
+		})
+	}
+}
+
+func TestAnalyzePageBlockedLinks(t *testing.T) {
+	guard, err := netguard.New([]string{"127.0.0.1"}, nil)
+	require.NoError(t, err)
+	utils.SetNetworkGuard(guard)
+	defer utils.SetNetworkGuard(testnet.LoopbackGuard())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><a href="http://10.0.0.1/admin">internal</a></body></html>`))
+	}))
+	defer ts.Close()
+
+	result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.BlockedLinks)
+	assert.Equal(t, 0, result.BrokenLinks)
+	assert.Contains(t, result.LinksStatus["http://10.0.0.1/admin"], "Blocked")
+}
+
+func TestAnalyzePageBodyLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<!DOCTYPE html><html><head><title>Big</title></head><body>"))
+		for i := 0; i < 1000; i++ {
+			_, _ = w.Write([]byte(strings.Repeat("x", 1024)))
+		}
+		_, _ = w.Write([]byte("</body></html>"))
+	}))
+	defer ts.Close()
+
+	result, err := analysis.AnalyzePageWithOptions(context.Background(), ts.URL, models.AnalysisOptions{
+		Limits: models.ParseLimits{MaxBodyBytes: 64 << 10},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, result.Truncated)
+	assert.Contains(t, result.TruncatedReason, "response body exceeded")
+	assert.Equal(t, "Big", result.Title)
+	assert.Equal(t, int64(64<<10), result.PageSize)
+}
+
+func TestAnalyzePageSettings(t *testing.T) {
+	var pageAgent, linkAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/linked" {
+			linkAgent = r.UserAgent()
+			return
+		}
+		pageAgent = r.UserAgent()
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><body><a href="/linked">x</a></body></html>`))
+	}))
+	defer ts.Close()
+
+	previous := analysis.CurrentSettings()
+	defer analysis.Configure(previous)
+
+	settings := analysis.DefaultSettings()
+	settings.UserAgent = "PageAgent/1.0"
+	settings.LinkChecker.UserAgent = "LinkAgent/1.0"
+	settings.Workers = 1
+	settings.LinkBuffer = 0
+	analysis.Configure(settings)
+
+	result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "PageAgent/1.0", pageAgent)
+	assert.Equal(t, "LinkAgent/1.0", linkAgent)
+	assert.Equal(t, "OK", result.LinksStatus[ts.URL+"/linked"])
+}
+
+func TestAnalyzePageAnyStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Set-Cookie", "session=secret")
+			w.Header().Add("X-Trace", "a")
+			w.Header().Add("X-Trace", "b")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Not here</title></head><body><a href="/">home</a></body></html>`))
+		case "/plain":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case "/soft":
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Page not found</title></head><body></body></html>`))
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	anyStatus := models.AnalysisOptions{AnyStatus: true}
+
+	t.Run("Rejected By Default", func(t *testing.T) {
+		_, err := analysis.AnalyzePage(ctx, ts.URL+"/gone")
+		assert.ErrorIs(t, err, analysis.ErrUpstreamStatus)
+	})
+
+	t.Run("Error Page", func(t *testing.T) {
+		result, err := analysis.AnalyzePageWithOptions(ctx, ts.URL+"/gone", anyStatus)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, result.StatusCode)
+		assert.Equal(t, "Not here", result.Title)
+		assert.Equal(t, 1, result.InternalLinks)
+		assert.Equal(t, "a, b", result.Headers["X-Trace"])
+		assert.NotContains(t, result.Headers, "Set-Cookie")
+		assert.False(t, result.Soft404, "only 200 responses can be soft 404s")
+	})
+
+	t.Run("Non-HTML Error", func(t *testing.T) {
+		_, err := analysis.AnalyzePageWithOptions(ctx, ts.URL+"/plain", anyStatus)
+		assert.ErrorIs(t, err, analysis.ErrUpstreamStatus)
+	})
+
+	t.Run("Soft 404", func(t *testing.T) {
+		result, err := analysis.AnalyzePage(ctx, ts.URL+"/soft")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.True(t, result.Soft404)
+		assert.Equal(t, `title contains "not found"`, result.Soft404Reason)
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		w := runHandler(httptest.NewRequest("GET", "/?any_status=true&url="+url.QueryEscape(ts.URL+"/gone"), nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var result models.PageAnalysis
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, http.StatusNotFound, result.StatusCode)
+	})
+}
+
+func TestAnalyzePageDeadline(t *testing.T) {
+	var inFlight, interrupted atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><body><a href="/fast">fast</a>` +
+				`<a href="/slow1">1</a><a href="/slow2">2</a><a href="/slow3">3</a></body></html>`))
+			return
+		}
+		if r.URL.Path == "/fast" {
+			return
+		}
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
+		select {
+		case <-r.Context().Done():
+			interrupted.Add(1)
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	previous := analysis.CurrentSettings()
+	defer analysis.Configure(previous)
+	settings := previous
+	settings.Timeout = 300 * time.Millisecond
+	settings.Workers = 1
+	settings.LinkBuffer = 0
+	settings.LinkChecker.Timeout = 10 * time.Second
+	analysis.Configure(settings)
+
+	t.Run("Partial Result", func(t *testing.T) {
+		start := time.Now()
+		result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		assert.True(t, result.Cancelled)
+		assert.Equal(t, "OK", result.LinksStatus[ts.URL+"/fast"])
+		assert.Equal(t, 4, result.InternalLinks)
+		assert.Equal(t, 3, result.SkippedLinks, "the unchecked links are counted as skipped")
+		assert.Zero(t, result.BrokenLinks)
+		assert.Eventually(t, func() bool { return inFlight.Load() == 0 }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(1), interrupted.Load(), "the link check in flight is cancelled")
+	})
+
+	t.Run("Caller Deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		result, err := analysis.AnalyzePage(ctx, ts.URL)
+		require.NoError(t, err)
+
+		assert.True(t, result.Cancelled)
+		assert.Equal(t, "OK", result.LinksStatus[ts.URL+"/fast"])
+		assert.Equal(t, 3, result.SkippedLinks)
+	})
+
+	t.Run("Analyze Caller Deadline", func(t *testing.T) {
+		// Analyze coalesces identical requests; every caller gets the partial result
+		// of the shared analysis once its deadline passes.
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		results := make([]*models.PageAnalysis, 2)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := analysis.Analyze(ctx, models.AnalyzeRequest{URL: ts.URL}, "deadline")
+				assert.NoError(t, err)
+				results[i] = result
+			}()
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			require.NotNil(t, result)
+			assert.True(t, result.Cancelled)
+			assert.Equal(t, "OK", result.LinksStatus[ts.URL+"/fast"])
+		}
+	})
+
+	t.Run("Caller Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		_, err := analysis.AnalyzePage(ctx, ts.URL)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestAnalyzePageConcurrentLinks runs several analyses of a page with many links at once;
+// run it with -race.
+func TestAnalyzePageConcurrentLinks(t *testing.T) {
+	const links = 600
+	var page strings.Builder
+	page.WriteString(`<!DOCTYPE html><html><body>`)
+	for i := range links {
+		fmt.Fprintf(&page, `<a href="/link/%d">%d</a>`, i, i)
+	}
+	page.WriteString(`</body></html>`)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(page.String()))
+			return
+		}
+		var n int
+		_, _ = fmt.Sscanf(r.URL.Path, "/link/%d", &n)
+		if n%3 == 0 {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	previous := analysis.CurrentSettings()
+	defer analysis.Configure(previous)
+	settings := previous
+	settings.Workers = 32
+	settings.LinkBuffer = 4
+	settings.Limits.MaxLinks = links
+	analysis.Configure(settings)
+
+	brokenBefore := testutil.ToFloat64(metrics.BrokenLinks)
+
+	const analyses = 4
+	var wg sync.WaitGroup
+	for range analyses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var reported int
+			result, err := analysis.AnalyzeStream(context.Background(), models.AnalyzeRequest{URL: ts.URL}, "", func(analysis.LinkResult) {
+				reported++ // onLink calls are serialized
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, links, result.InternalLinks)
+			assert.Equal(t, links/3, result.BrokenLinks)
+			assert.Len(t, result.LinksStatus, links)
+			assert.Equal(t, links, reported)
+			assert.Equal(t, "Status: 404 Not Found", result.LinksStatus[ts.URL+"/link/0"])
+			assert.Equal(t, "OK", result.LinksStatus[ts.URL+"/link/1"])
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, float64(analyses*links/3), testutil.ToFloat64(metrics.BrokenLinks)-brokenBefore)
+}
+
+func runHandler(req *http.Request) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	analysis.HandleAnalyze(c)
+	return w
+}
+
+func TestHandleAnalyzeOptionValidation(t *testing.T) {
+	t.Run("Query Parameters", func(t *testing.T) {
+		w := runHandler(httptest.NewRequest("GET", "/?url=https://example.com"+
+			"&sections=title,bogus&link_timeout=soon&link_concurrency=-1&max_links=x&include=(&check_links=maybe", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Invalid analysis options", resp.Error)
+
+		fields := make(map[string]string)
+		for _, f := range resp.Fields {
+			fields[f.Field] = f.Message
+		}
+		assert.Contains(t, fields["sections"], `"bogus"`)
+		assert.Contains(t, fields["link_timeout"], "duration")
+		assert.Contains(t, fields["link_concurrency"], "between 1 and")
+		assert.Contains(t, fields["max_links"], "integer")
+		assert.Contains(t, fields["include"], "invalid include pattern")
+		assert.Contains(t, fields["check_links"], "true or false")
+	})
+
+	t.Run("Limits Above Server Settings", func(t *testing.T) {
+		body := `{"url": "https://example.com", "max_links": 1000000, "link_timeout": "10m"}`
+		w := runHandler(httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"max_links"`)
+		assert.Contains(t, w.Body.String(), `"field":"link_timeout"`)
+	})
+
+	t.Run("Unknown JSON Field", func(t *testing.T) {
+		w := runHandler(httptest.NewRequest("POST", "/", strings.NewReader(`{"url": "https://example.com", "sectons": []}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"body"`)
+		assert.Contains(t, w.Body.String(), "sectons")
+	})
+
+	t.Run("Header Injection", func(t *testing.T) {
+		body := `{"url": "https://example.com", "user_agent": "a\r\nX-Injected: 1"}`
+		w := runHandler(httptest.NewRequest("POST", "/", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"user_agent"`)
+	})
+}
+
+func TestHandleAnalyzeOptions(t *testing.T) {
+	var mu sync.Mutex
+	checked := make(map[string]string)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			mu.Lock()
+			checked[r.URL.Path] = r.UserAgent()
+			mu.Unlock()
+			return
+		}
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Options</title></head><body>
+			<h1>Heading</h1>
+			<a href="/docs/a">a</a><a href="/docs/b">b</a><a href="/blog/c">c</a><a href="/docs/private">d</a>
+			</body></html>`))
+	}))
+	defer ts.Close()
+
+	t.Run("Skip Link Check", func(t *testing.T) {
+		w := runHandler(httptest.NewRequest("GET", "/?check_links=false&url="+ts.URL, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result models.PageAnalysis
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, 4, result.InternalLinks)
+		assert.Equal(t, 4, result.SkippedLinks)
+		assert.Empty(t, result.LinksStatus)
+	})
+
+	t.Run("Sections", func(t *testing.T) {
+		w := runHandler(httptest.NewRequest("GET", "/?sections=title&url="+ts.URL, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result models.PageAnalysis
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, "Options", result.Title)
+		assert.Empty(t, result.Headings)
+		assert.Empty(t, result.HTMLVersion)
+		assert.Zero(t, result.InternalLinks)
+	})
+
+	t.Run("Filters And User Agent", func(t *testing.T) {
+		body := `{"url": "` + ts.URL + `", "include": ["/docs/"], "exclude": ["private$"],
+			"user_agent": "CustomAgent/3.0", "link_concurrency": 1, "link_timeout": "2s"}`
+		w := runHandler(httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result models.PageAnalysis
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, 2, result.SkippedLinks)
+		assert.Len(t, result.LinksStatus, 2)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, map[string]string{"/docs/a": "CustomAgent/3.0", "/docs/b": "CustomAgent/3.0"}, checked)
+	})
+}
@@ -0,0 +1,84 @@
+package analysis_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/utils"
+)
+
+// linkSite serves a page with n internal links, each answering 200.
+func linkSite(b *testing.B, n int) (*httptest.Server, *atomic.Int64) {
+	b.Helper()
+	var page strings.Builder
+	page.WriteString(`<!DOCTYPE html><html><head><title>Links</title></head><body>`)
+	for i := range n {
+		fmt.Fprintf(&page, `<a href="/link/%d">%d</a>`, i, i)
+	}
+	page.WriteString(`</body></html>`)
+	body := []byte(page.String())
+
+	var conns atomic.Int64
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write(body)
+		}
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.Start()
+	b.Cleanup(ts.Close)
+	return ts, &conns
+}
+
+// BenchmarkAnalyzePageLinks analyzes pages with 1,000+ links on one host, once with the
+// default pool and once keeping too few idle connections for the link workers, which
+// makes most link checks open a new connection.
+func BenchmarkAnalyzePageLinks(b *testing.B) {
+	previousTransport := utils.CurrentTransportSettings()
+	defer utils.SetTransportSettings(previousTransport)
+	previous := analysis.CurrentSettings()
+	defer analysis.Configure(previous)
+
+	for _, links := range []int{1000, 5000} {
+		for _, idle := range []int{2, utils.DefaultTransportSettings().MaxIdleConnsPerHost} {
+			b.Run(fmt.Sprintf("links=%d/idle_per_host=%d", links, idle), func(b *testing.B) {
+				transport := utils.DefaultTransportSettings()
+				transport.MaxIdleConnsPerHost = idle
+				utils.SetTransportSettings(transport)
+
+				settings := previous
+				settings.Workers = 32
+				settings.Limits.MaxLinks = links
+				analysis.Configure(settings)
+
+				ts, conns := linkSite(b, links)
+
+				var runs int
+				for b.Loop() {
+					runs++
+					result, err := analysis.AnalyzePage(context.Background(), ts.URL)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if result.InternalLinks != links {
+						b.Fatalf("checked %d links, want %d", result.InternalLinks, links)
+					}
+				}
+
+				b.ReportMetric(float64(links*runs)/b.Elapsed().Seconds(), "links/s")
+				b.ReportMetric(float64(conns.Load())/float64(runs), "conns/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package analysis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/models"
+)
+
+// cachedSite serves a page with configurable caching headers and records how it was requested.
+type cachedSite struct {
+	mu           sync.Mutex
+	title        string
+	etag         string
+	cacheControl string
+	fetches      int
+	conditional  []string // If-None-Match of each request
+}
+
+func (s *cachedSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetches++
+	s.conditional = append(s.conditional, r.Header.Get("If-None-Match"))
+	if s.cacheControl != "" {
+		w.Header().Set("Cache-Control", s.cacheControl)
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+		if r.Header.Get("If-None-Match") == s.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>` + s.title + `</title></head><body></body></html>`))
+}
+
+func (s *cachedSite) set(f func(s *cachedSite)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+func newCacheRouter(cfg analysis.CacheConfig) *gin.Engine {
+	analysis.SetResultCache(analysis.NewResultCache(cfg))
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/analyze", analysis.HandleAnalyze)
+	return r
+}
+
+var defaultCacheConfig = analysis.CacheConfig{MaxEntries: 10, DefaultTTL: time.Minute, MaxAge: time.Hour}
+
+type cachedResponse struct {
+	cache string
+	age   string
+	title string
+}
+
+func analyzeCached(t *testing.T, router *gin.Engine, query string, header http.Header) cachedResponse {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/analyze?"+query, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result models.PageAnalysis
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return cachedResponse{cache: w.Header().Get("X-Cache"), age: w.Header().Get("Age"), title: result.Title}
+}
+
+func TestResultCache(t *testing.T) {
+	defer analysis.SetResultCache(nil)
+
+	t.Run("Fresh Results Are Served From Cache", func(t *testing.T) {
+		site := &cachedSite{title: "Cached", cacheControl: "max-age=60"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(defaultCacheConfig)
+		query := "url=" + url.QueryEscape(ts.URL)
+
+		assert.Equal(t, cachedResponse{cache: "MISS", title: "Cached"}, analyzeCached(t, router, query, nil))
+		hit := analyzeCached(t, router, query, nil)
+		assert.Equal(t, "HIT", hit.cache)
+		assert.Equal(t, "0", hit.age)
+		assert.Equal(t, "Cached", hit.title)
+		assert.Equal(t, 1, site.fetches)
+	})
+
+	t.Run("Normalized URLs Share An Entry", func(t *testing.T) {
+		site := &cachedSite{title: "Normalized", cacheControl: "max-age=60"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(defaultCacheConfig)
+
+		upper := strings.Replace(ts.URL, "http://", "HTTP://", 1)
+		assert.Equal(t, "MISS", analyzeCached(t, router, "url="+url.QueryEscape(upper+"/?b=2&a=1#top"), nil).cache)
+		assert.Equal(t, "HIT", analyzeCached(t, router, "url="+url.QueryEscape(ts.URL+"?a=1&b=2"), nil).cache)
+	})
+
+	t.Run("Options Are Part Of The Key", func(t *testing.T) {
+		site := &cachedSite{title: "Options", cacheControl: "max-age=60"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(defaultCacheConfig)
+		query := "url=" + url.QueryEscape(ts.URL)
+
+		assert.Equal(t, "MISS", analyzeCached(t, router, query, nil).cache)
+		assert.Equal(t, "MISS", analyzeCached(t, router, query+"&sections=title,links", nil).cache)
+		assert.Equal(t, "HIT", analyzeCached(t, router, query+"&sections=links&sections=title", nil).cache)
+	})
+
+	t.Run("Stale Results Are Revalidated", func(t *testing.T) {
+		site := &cachedSite{title: "Before", etag: `"v1"`, cacheControl: "no-cache"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(defaultCacheConfig)
+		query := "url=" + url.QueryEscape(ts.URL)
+
+		assert.Equal(t, "MISS", analyzeCached(t, router, query, nil).cache)
+		assert.Equal(t, cachedResponse{cache: "HIT", age: "0", title: "Before"}, analyzeCached(t, router, query, nil))
+
+		site.set(func(s *cachedSite) { s.title, s.etag = "After", `"v2"` })
+		assert.Equal(t, cachedResponse{cache: "MISS", title: "After"}, analyzeCached(t, router, query, nil))
+
+		assert.Equal(t, []string{"", `"v1"`, `"v1"`}, site.conditional)
+	})
+
+	t.Run("Caller No-Cache Forces A Fresh Run", func(t *testing.T) {
+		site := &cachedSite{title: "Fresh", etag: `"v1"`, cacheControl: "max-age=60"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(defaultCacheConfig)
+		query := "url=" + url.QueryEscape(ts.URL)
+
+		analyzeCached(t, router, query, nil)
+		fresh := analyzeCached(t, router, query, http.Header{"Cache-Control": {"no-cache"}})
+		assert.Equal(t, "MISS", fresh.cache)
+		assert.Equal(t, []string{"", ""}, site.conditional, "a forced run is not conditional")
+
+		assert.Equal(t, "HIT", analyzeCached(t, router, query, nil).cache)
+		assert.Equal(t, "MISS", analyzeCached(t, router, query, http.Header{"Pragma": {"no-cache"}}).cache)
+	})
+
+	t.Run("No-Store Pages Are Not Cached", func(t *testing.T) {
+		site := &cachedSite{title: "Private", cacheControl: "private, no-store"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(defaultCacheConfig)
+		query := "url=" + url.QueryEscape(ts.URL)
+
+		assert.Equal(t, "MISS", analyzeCached(t, router, query, nil).cache)
+		assert.Equal(t, "MISS", analyzeCached(t, router, query, nil).cache)
+		assert.Equal(t, 2, site.fetches)
+	})
+
+	t.Run("Entries Expire After Max Age", func(t *testing.T) {
+		site := &cachedSite{title: "Old", etag: `"v1"`, cacheControl: "max-age=3600"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(analysis.CacheConfig{MaxEntries: 10, DefaultTTL: time.Minute, MaxAge: 50 * time.Millisecond})
+		query := "url=" + url.QueryEscape(ts.URL)
+
+		analyzeCached(t, router, query, nil)
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, "MISS", analyzeCached(t, router, query, nil).cache)
+		assert.Equal(t, []string{"", ""}, site.conditional, "expired entries are not revalidated")
+	})
+
+	t.Run("Least Recently Used Entries Are Evicted", func(t *testing.T) {
+		site := &cachedSite{title: "Evicted", cacheControl: "max-age=60"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(analysis.CacheConfig{MaxEntries: 1, DefaultTTL: time.Minute, MaxAge: time.Hour})
+
+		analyzeCached(t, router, "url="+url.QueryEscape(ts.URL+"/a"), nil)
+		analyzeCached(t, router, "url="+url.QueryEscape(ts.URL+"/b"), nil)
+		assert.Equal(t, "MISS", analyzeCached(t, router, "url="+url.QueryEscape(ts.URL+"/a"), nil).cache)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		site := &cachedSite{title: "Uncached", cacheControl: "max-age=60"}
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCacheRouter(defaultCacheConfig)
+		analysis.SetResultCache(nil)
+
+		assert.Empty(t, analyzeCached(t, router, "url="+url.QueryEscape(ts.URL), nil).cache)
+	})
+}
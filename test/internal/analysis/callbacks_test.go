@@ -0,0 +1,201 @@
+package analysis_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/webhook"
+)
+
+const callbackSecret = "callback-secret"
+
+type callback struct {
+	header  http.Header
+	payload models.CallbackPayload
+	valid   bool
+}
+
+// callbackReceiver is a local endpoint forwarding every verified callback to a channel.
+func callbackReceiver() (*httptest.Server, chan callback) {
+	received := make(chan callback, 4)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		cb := callback{header: r.Header.Clone()}
+		cb.valid = webhook.Verify(callbackSecret, r.Header, body, time.Now(), time.Minute) == nil
+		_ = json.Unmarshal(body, &cb.payload)
+		received <- cb
+	}))
+	return ts, received
+}
+
+func newCallbackSender() *webhook.Sender {
+	sender := webhook.NewSender(callbackSecret, webhook.RetryPolicy{MaxAttempts: 1}, 5*time.Second)
+	sender.Log = webhook.NewDeliveryLog(10)
+	return sender
+}
+
+func TestAnalyzeCallback(t *testing.T) {
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Async</title></head><body></body></html>`))
+	}))
+	defer page.Close()
+	receiver, received := callbackReceiver()
+	defer receiver.Close()
+
+	sender := newCallbackSender()
+	analysis.SetCallbackSender(sender)
+	defer analysis.SetCallbackSender(nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("requestID", "req-1") })
+	router.POST("/analyze", analysis.HandleAnalyze)
+
+	analyze := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/analyze", strings.NewReader(body)))
+		return w
+	}
+	await := func(t *testing.T) callback {
+		select {
+		case cb := <-received:
+			return cb
+		case <-time.After(5 * time.Second):
+			t.Fatal("callback not delivered")
+			return callback{}
+		}
+	}
+
+	t.Run("Completed", func(t *testing.T) {
+		w := analyze(`{"url": "` + page.URL + `", "callback_url": "` + receiver.URL + `"}`)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+		var accepted models.CallbackAccepted
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+		assert.Equal(t, "req-1", accepted.RequestID)
+		assert.Equal(t, receiver.URL, accepted.CallbackURL)
+
+		cb := await(t)
+		assert.True(t, cb.valid, "signature must verify")
+		assert.Equal(t, analysis.EventAnalysisCompleted, cb.header.Get(webhook.HeaderEvent))
+		assert.Equal(t, accepted.DeliveryID, cb.header.Get(webhook.HeaderID))
+		assert.Equal(t, analysis.EventAnalysisCompleted, cb.payload.Event)
+		assert.Equal(t, "req-1", cb.payload.RequestID)
+		require.NotNil(t, cb.payload.Analysis)
+		assert.Equal(t, "Async", cb.payload.Analysis.Title)
+		assert.Nil(t, cb.payload.Error)
+
+		require.Eventually(t, func() bool {
+			d, ok := sender.Log.Get("", accepted.DeliveryID)
+			return ok && d.Status == webhook.StatusDelivered
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		w := analyze(`{"url": "` + closed.URL + `", "callback_url": "` + receiver.URL + `"}`)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		cb := await(t)
+		assert.True(t, cb.valid)
+		assert.Equal(t, analysis.EventAnalysisFailed, cb.payload.Event)
+		assert.Nil(t, cb.payload.Analysis)
+		require.NotNil(t, cb.payload.Error)
+		assert.Equal(t, models.CodeUpstreamUnreachable, cb.payload.Error.Code)
+		assert.Equal(t, "Page unreachable", cb.payload.Error.Error)
+	})
+
+	t.Run("Query Parameter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.GET("/analyze", analysis.HandleAnalyze)
+		router.ServeHTTP(w, httptest.NewRequest("GET",
+			"/analyze?url="+url.QueryEscape(page.URL)+"&callback_url="+url.QueryEscape(receiver.URL), nil))
+		require.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, analysis.EventAnalysisCompleted, await(t).payload.Event)
+	})
+}
+
+func TestAnalyzeCallbackValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		body     string
+		contains string
+	}{
+		{"Callbacks Disabled", false, `{"url": "https://example.com", "callback_url": "https://hooks.example.com"}`, "not enabled"},
+		{"Invalid Callback URL", true, `{"url": "https://example.com", "callback_url": "ftp://hooks.example.com"}`, "absolute http or https"},
+		{"Blocked Callback Address", true, `{"url": "https://example.com", "callback_url": "http://169.254.169.254/latest"}`, "destination not allowed"},
+		{"Invalid Page URL", true, `{"url": "example.com", "callback_url": "https://hooks.example.com"}`, `"field":"url"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.enabled {
+				analysis.SetCallbackSender(newCallbackSender())
+				defer analysis.SetCallbackSender(nil)
+			}
+
+			w := runHandler(httptest.NewRequest("POST", "/api/v1/analyze", strings.NewReader(tt.body)))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.contains)
+		})
+	}
+}
+
+func TestDrainCallbacks(t *testing.T) {
+	release := make(chan struct{})
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Slow</title></head><body></body></html>`))
+	}))
+	defer page.Close()
+	receiver, received := callbackReceiver()
+	defer receiver.Close()
+
+	sender := newCallbackSender()
+	analysis.SetCallbackSender(sender)
+	defer analysis.SetCallbackSender(nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("apiKeyID", "ci") })
+	router.POST("/analyze", analysis.HandleAnalyze)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/analyze",
+		strings.NewReader(`{"url": "`+page.URL+`", "callback_url": "`+receiver.URL+`"}`)))
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var accepted models.CallbackAccepted
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, analysis.DrainCallbacks(ctx), context.DeadlineExceeded)
+
+	close(release)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, analysis.DrainCallbacks(ctx))
+	require.Len(t, received, 1, "draining waits for the delivery")
+
+	delivery, ok := sender.Log.Get("ci", accepted.DeliveryID)
+	require.True(t, ok)
+	assert.Equal(t, "ci", delivery.KeyID)
+	_, ok = sender.Log.Get("dashboard", accepted.DeliveryID)
+	assert.False(t, ok, "other keys cannot see the delivery")
+}
@@ -0,0 +1,191 @@
+package analysis_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/models"
+	"web-analyzer/pkg/metrics"
+)
+
+// slowSite holds every page request until release is closed.
+type slowSite struct {
+	fetches   atomic.Int32
+	arrived   chan struct{}
+	release   chan struct{}
+	cancelled chan struct{}
+}
+
+func newSlowSite() *slowSite {
+	return &slowSite{
+		arrived:   make(chan struct{}, 16),
+		release:   make(chan struct{}),
+		cancelled: make(chan struct{}, 16),
+	}
+}
+
+func (s *slowSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.fetches.Add(1)
+	s.arrived <- struct{}{}
+	select {
+	case <-s.release:
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Slow</title></head><body></body></html>`))
+	case <-r.Context().Done():
+		s.cancelled <- struct{}{}
+	}
+}
+
+func newCoalesceRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/analyze", analysis.HandleAnalyze)
+	return r
+}
+
+func TestRequestCoalescing(t *testing.T) {
+	t.Run("Concurrent Requests Share One Analysis", func(t *testing.T) {
+		site := newSlowSite()
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCoalesceRouter()
+		before := testutil.ToFloat64(metrics.CoalescedRequests)
+
+		const callers = 10
+		responses := make([]*httptest.ResponseRecorder, callers)
+		var wg sync.WaitGroup
+		for i := range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				responses[i] = httptest.NewRecorder()
+				router.ServeHTTP(responses[i], httptest.NewRequest("GET", "/analyze?url="+url.QueryEscape(ts.URL), nil))
+			}()
+		}
+
+		<-site.arrived
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(metrics.CoalescedRequests)-before == callers-1
+		}, 5*time.Second, 5*time.Millisecond)
+		close(site.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), site.fetches.Load())
+		for _, w := range responses {
+			require.Equal(t, http.StatusOK, w.Code)
+			var result models.PageAnalysis
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, "Slow", result.Title)
+		}
+	})
+
+	t.Run("Different Options Are Not Coalesced", func(t *testing.T) {
+		site := newSlowSite()
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCoalesceRouter()
+
+		var wg sync.WaitGroup
+		for _, query := range []string{"", "&sections=title"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/analyze?url="+url.QueryEscape(ts.URL)+query, nil))
+			}()
+		}
+		<-site.arrived
+		<-site.arrived
+		close(site.release)
+		wg.Wait()
+		assert.Equal(t, int32(2), site.fetches.Load())
+	})
+
+	t.Run("A Cancelled Caller Does Not Cancel The Others", func(t *testing.T) {
+		site := newSlowSite()
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		router := newCoalesceRouter()
+		target := "/analyze?url=" + url.QueryEscape(ts.URL)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := httptest.NewRecorder()
+		firstDone := make(chan struct{})
+		go func() {
+			router.ServeHTTP(first, httptest.NewRequest("GET", target, nil).WithContext(ctx))
+			close(firstDone)
+		}()
+		<-site.arrived
+
+		before := testutil.ToFloat64(metrics.CoalescedRequests)
+		second := httptest.NewRecorder()
+		secondDone := make(chan struct{})
+		go func() {
+			router.ServeHTTP(second, httptest.NewRequest("GET", target, nil))
+			close(secondDone)
+		}()
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(metrics.CoalescedRequests) > before
+		}, 5*time.Second, 5*time.Millisecond)
+
+		cancel()
+		<-firstDone
+		assert.Equal(t, http.StatusRequestTimeout, first.Code)
+
+		close(site.release)
+		<-secondDone
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, int32(1), site.fetches.Load())
+	})
+
+	t.Run("The Analysis Stops When Every Caller Is Gone", func(t *testing.T) {
+		site := newSlowSite()
+		ts := httptest.NewServer(site)
+		defer ts.Close()
+		defer close(site.release)
+		router := newCoalesceRouter()
+		target := "/analyze?url=" + url.QueryEscape(ts.URL)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil).WithContext(ctx))
+			}()
+		}
+		<-site.arrived
+		cancel()
+		wg.Wait()
+
+		select {
+		case <-site.cancelled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("page fetch was not cancelled")
+		}
+
+		// A new request starts a new analysis instead of joining the cancelled one.
+		done := make(chan int)
+		go func() {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+			done <- w.Code
+		}()
+		<-site.arrived
+		assert.Equal(t, int32(2), site.fetches.Load())
+		site.release <- struct{}{}
+		assert.Equal(t, http.StatusOK, <-done)
+	})
+}
@@ -0,0 +1,130 @@
+package analysis_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/models"
+)
+
+func TestAnalyzeErrors(t *testing.T) {
+	previous := analysis.CurrentSettings()
+	defer analysis.Configure(previous)
+	settings := previous
+	settings.FetchTimeout = 200 * time.Millisecond
+	analysis.Configure(settings)
+
+	release := make(chan struct{})
+	defer close(release)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case "/slow":
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		case "/truncated":
+			w.Header().Set("Content-Length", "1000")
+			_, _ = w.Write([]byte("<html>"))
+		}
+	}))
+	defer ts.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		kind   error
+		status int
+		code   string
+	}{
+		{"Error Status", ts.URL + "/error", analysis.ErrUpstreamStatus, http.StatusBadGateway, models.CodeUpstreamStatus},
+		{"Unreachable", closed.URL, analysis.ErrUpstreamUnreachable, http.StatusUnprocessableEntity, models.CodeUpstreamUnreachable},
+		{"Timeout", ts.URL + "/slow", analysis.ErrTimeout, http.StatusGatewayTimeout, models.CodeTimeout},
+		{"Truncated Body", ts.URL + "/truncated", analysis.ErrUpstreamInvalid, http.StatusBadGateway, models.CodeUpstreamInvalid},
+		{"Blocked", "http://169.254.169.254/latest/meta-data/", analysis.ErrBlocked, http.StatusForbidden, models.CodeBlocked},
+		{"Invalid URL", "not-a-valid-url", analysis.ErrInvalidInput, http.StatusBadRequest, models.CodeInvalidInput},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/analyze", analysis.HandleAnalyze)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := analysis.AnalyzePage(context.Background(), tt.url)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.kind)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/analyze?url="+url.QueryEscape(tt.url), nil))
+			assert.Equal(t, tt.status, w.Code)
+
+			var resp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp.Code)
+			assert.NotEmpty(t, resp.Error)
+		})
+	}
+
+	t.Run("Upstream Status Code", func(t *testing.T) {
+		_, err := analysis.AnalyzePage(context.Background(), ts.URL+"/error")
+		var analysisErr *analysis.Error
+		require.True(t, errors.As(err, &analysisErr))
+		assert.Equal(t, http.StatusInternalServerError, analysisErr.StatusCode)
+	})
+}
+
+func TestAnalyzeErrorFormats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("requestID", "problem-request") })
+	router.POST("/analyze", analysis.HandleAnalyze)
+
+	t.Run("Body Too Large", func(t *testing.T) {
+		body := `{"url": "https://example.com", "user_agent": "` + strings.Repeat("a", 70<<10) + `"}`
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/analyze", strings.NewReader(body)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, models.CodeTooLarge, resp.Code)
+	})
+
+	t.Run("Problem Details", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/analyze", strings.NewReader(`{"url": "https://example.com", "sections": ["nope"]}`))
+		req.Header.Set("Accept", apierror.ProblemMediaType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, apierror.ProblemMediaType, w.Header().Get("Content-Type"))
+
+		var problem models.Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, models.ProblemTypePrefix+models.CodeInvalidInput, problem.Type)
+		assert.Equal(t, models.CodeInvalidInput, problem.Code)
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "/analyze", problem.Instance)
+		assert.Equal(t, "problem-request", problem.RequestID)
+		require.Len(t, problem.Fields, 1)
+		assert.Equal(t, "sections", problem.Fields[0].Field)
+	})
+}
@@ -0,0 +1,104 @@
+package analysis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/storage"
+)
+
+func TestResponseFormats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Formats</title></head><body><a href="/missing">gone</a></body></html>`))
+	}))
+	defer ts.Close()
+
+	analysis.SetResultStore(storage.NewMemoryStore(storage.Retention{}))
+	defer analysis.SetResultStore(nil)
+	router := newResultsRouter()
+
+	request := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	analyzePath := "/analyze?url=" + url.QueryEscape(ts.URL)
+
+	t.Run("JSON By Default", func(t *testing.T) {
+		w := request(analyzePath, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Contains(t, w.Header().Values("Vary"), "Accept")
+
+		w = request(analyzePath, "image/png")
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json", "unsupported types fall back to JSON")
+	})
+
+	t.Run("Accept Header", func(t *testing.T) {
+		cases := map[string]string{
+			"text/csv":               "page_url,link_url,state,status\n",
+			"text/html":              "<!DOCTYPE html>",
+			"application/xml":        "<testsuites",
+			"application/sarif+json": `"version": "2.1.0"`,
+		}
+		for accept, want := range cases {
+			w := request(analyzePath, accept)
+			require.Equal(t, http.StatusOK, w.Code, accept)
+			assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), accept), accept)
+			assert.Contains(t, w.Body.String(), want, accept)
+		}
+	})
+
+	t.Run("Format Parameter Overrides Accept", func(t *testing.T) {
+		w := request(analyzePath+"&format=csv", "text/html")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), ts.URL+"/missing,broken,Status: 404 Not Found")
+	})
+
+	t.Run("Unknown Format", func(t *testing.T) {
+		w := request(analyzePath+"&format=xlsx", "")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Fields, 1)
+		assert.Equal(t, "format", resp.Fields[0].Field)
+	})
+
+	t.Run("Stored Results", func(t *testing.T) {
+		w := request(analyzePath, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		resultID := w.Header().Get("X-Result-ID")
+		require.NotEmpty(t, resultID)
+
+		w = request("/results/"+resultID+"?format=junit", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `name="result_id" value="`+resultID+`"`)
+		assert.Contains(t, w.Body.String(), `failures="1"`)
+
+		w = request("/results/"+resultID, "application/json")
+		var record models.AnalysisRecord
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &record), "JSON keeps the record shape")
+		assert.Equal(t, resultID, record.ID)
+
+		assert.Equal(t, http.StatusBadRequest, request("/results/"+resultID+"?format=pdf", "").Code)
+	})
+}
@@ -0,0 +1,15 @@
+package analysis_test
+
+import (
+	"os"
+	"testing"
+
+	"web-analyzer/test/internal/testnet"
+)
+
+// TestMain lets the analyzer reach httptest servers, which listen on loopback
+// addresses that the default network guard blocks.
+func TestMain(m *testing.M) {
+	testnet.AllowLoopback()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,175 @@
+package analysis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/diff"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/storage"
+)
+
+func newResultsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/analyze", analysis.HandleAnalyze)
+	r.GET("/results/:id", analysis.HandleGetResult)
+	r.GET("/history", analysis.HandleHistory)
+	r.GET("/diff", analysis.HandleDiff)
+	return r
+}
+
+func TestResultStorage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Stored</title></head><body></body></html>`))
+	}))
+	defer ts.Close()
+
+	analysis.SetResultStore(storage.NewMemoryStore(storage.Retention{}))
+	defer analysis.SetResultStore(nil)
+
+	router := newResultsRouter()
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	var resultIDs []string
+	for i := 0; i < 3; i++ {
+		w := get("/analyze?url=" + url.QueryEscape(ts.URL))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result models.PageAnalysis
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.NotEmpty(t, result.ResultID)
+		assert.Equal(t, result.ResultID, w.Header().Get("X-Result-ID"))
+		resultIDs = append(resultIDs, result.ResultID)
+	}
+
+	t.Run("Get Result", func(t *testing.T) {
+		w := get("/results/" + resultIDs[0])
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var record models.AnalysisRecord
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &record))
+		assert.Equal(t, ts.URL, record.URL)
+		assert.Equal(t, "Stored", record.Analysis.Title)
+		assert.False(t, record.CreatedAt.IsZero())
+
+		assert.Equal(t, http.StatusNotFound, get("/results/unknown").Code)
+	})
+
+	t.Run("History", func(t *testing.T) {
+		w := get("/history?limit=2&url=" + url.QueryEscape(ts.URL))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var history models.HistoryResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+		assert.Equal(t, 3, history.Total)
+		assert.Equal(t, 2, history.Limit)
+		require.Len(t, history.Results, 2)
+		assert.Equal(t, resultIDs[2], history.Results[0].ID)
+		assert.Equal(t, resultIDs[1], history.Results[1].ID)
+	})
+
+	t.Run("History Validation", func(t *testing.T) {
+		w := get("/history?limit=1000&offset=-1")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		for _, field := range []string{`"field":"url"`, `"field":"limit"`, `"field":"offset"`} {
+			assert.Contains(t, w.Body.String(), field)
+		}
+	})
+}
+
+func TestDiffResults(t *testing.T) {
+	title := "Before"
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>` + title + `</title></head>
+				<body><a href="/kept">kept</a><a href="/` + title + `">changing</a></body></html>`))
+		case "/kept", "/Before":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	analysis.SetResultStore(storage.NewMemoryStore(storage.Retention{}))
+	defer analysis.SetResultStore(nil)
+
+	router := newResultsRouter()
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	analyze := func() string {
+		w := serve(httptest.NewRequest("GET", "/analyze?url="+url.QueryEscape(ts.URL), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Header().Get("X-Result-ID")
+	}
+
+	from := analyze()
+	mu.Lock()
+	title = "After"
+	mu.Unlock()
+	to := analyze()
+
+	t.Run("JSON", func(t *testing.T) {
+		w := serve(httptest.NewRequest("GET", "/diff?from="+from+"&to="+to, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var report diff.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.True(t, report.Changed)
+		assert.Equal(t, &diff.ValueChange{From: "Before", To: "After"}, report.Title)
+		// /kept is checked by both analyses, so it is neither added nor removed.
+		assert.Equal(t, []string{ts.URL + "/After"}, report.Links.Added)
+		assert.Equal(t, []string{ts.URL + "/Before"}, report.Links.Removed)
+		require.Len(t, report.Links.NowBroken, 1)
+		assert.Equal(t, ts.URL+"/After", report.Links.NowBroken[0].URL)
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		w := serve(httptest.NewRequest("GET", "/diff?format=text&from="+from+"&to="+to, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+		assert.Contains(t, w.Body.String(), `Title: "Before" -> "After"`)
+
+		req := httptest.NewRequest("GET", "/diff?from="+from+"&to="+to, nil)
+		req.Header.Set("Accept", "text/plain")
+		assert.Contains(t, serve(req).Body.String(), "now broken")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		w := serve(httptest.NewRequest("GET", "/diff?from="+from+"&format=xml", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"to"`)
+		assert.Contains(t, w.Body.String(), `"field":"format"`)
+
+		w = serve(httptest.NewRequest("GET", "/diff?from="+from+"&to=missing", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestResultStorageDisabled(t *testing.T) {
+	analysis.SetResultStore(nil)
+
+	w := httptest.NewRecorder()
+	newResultsRouter().ServeHTTP(w, httptest.NewRequest("GET", "/results/any", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
@@ -0,0 +1,85 @@
+package analysis_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/storage"
+)
+
+func TestAnalyzeService(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Service</title></head><body>` +
+			`<a href="/ok">ok</a><a href="/missing">gone</a><a href="mailto:team@example.com">mail</a></body></html>`))
+	}))
+	defer ts.Close()
+
+	store := storage.NewMemoryStore(storage.Retention{})
+	analysis.SetResultStore(store)
+	defer analysis.SetResultStore(nil)
+	ctx := context.Background()
+
+	t.Run("Analyze", func(t *testing.T) {
+		result, err := analysis.Analyze(ctx, models.AnalyzeRequest{URL: ts.URL}, "service-request")
+		require.NoError(t, err)
+		assert.Equal(t, "Service", result.Title)
+		assert.Equal(t, 1, result.BrokenLinks)
+
+		record, err := store.Get(ctx, result.ResultID)
+		require.NoError(t, err)
+		assert.Equal(t, "service-request", record.RequestID)
+	})
+
+	t.Run("Stream Reports Each Checked Link", func(t *testing.T) {
+		var mu sync.Mutex
+		statuses := map[string]string{}
+		result, err := analysis.AnalyzeStream(ctx, models.AnalyzeRequest{URL: ts.URL}, "stream-request", func(link analysis.LinkResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[link.URL] = link.Status
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, result.LinksStatus, statuses, "links that are not requested are not reported")
+		assert.Equal(t, "OK", statuses[ts.URL+"/ok"])
+		assert.NotEmpty(t, result.ResultID)
+		assert.NotEmpty(t, result.AnalysisDuration)
+	})
+
+	t.Run("Invalid Requests", func(t *testing.T) {
+		cases := map[string]struct {
+			req    models.AnalyzeRequest
+			fields []string
+		}{
+			"Missing URL":  {models.AnalyzeRequest{}, []string{"url"}},
+			"Relative URL": {models.AnalyzeRequest{URL: "/about"}, []string{"url"}},
+			"Options":      {models.AnalyzeRequest{URL: ts.URL, LinkTimeout: "soon", MaxLinks: -1}, []string{"link_timeout", "max_links"}},
+			"Callback":     {models.AnalyzeRequest{URL: ts.URL, CallbackURL: "https://hooks.example.com"}, []string{"callback_url"}},
+		}
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := analysis.AnalyzeStream(ctx, tc.req, "", nil)
+				var reqErr *analysis.RequestError
+				require.ErrorAs(t, err, &reqErr)
+
+				var fields []string
+				for _, f := range reqErr.Fields {
+					fields = append(fields, f.Field)
+				}
+				assert.ElementsMatch(t, tc.fields, fields)
+			})
+		}
+	})
+}
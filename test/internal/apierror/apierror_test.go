@@ -0,0 +1,75 @@
+package apierror_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/apierror"
+	"web-analyzer/internal/models"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/things/:id", func(c *gin.Context) {
+		c.Set("requestID", "req-1")
+		apierror.Abort(c, http.StatusNotFound, models.ErrorResponse{
+			Code:    models.CodeNotFound,
+			Error:   "Thing not found",
+			Details: "No thing has this id",
+		})
+	}, func(c *gin.Context) {
+		t.Error("handler chain was not aborted")
+	})
+
+	tests := []struct {
+		name    string
+		accept  string
+		problem bool
+	}{
+		{"No Accept", "", false},
+		{"JSON", "application/json", false},
+		{"Problem", "application/problem+json", true},
+		{"Problem Among Others", "application/json;q=0.5, application/problem+json", true},
+		{"Problem Refused", "application/problem+json;q=0", false},
+		{"Malformed", "application/problem+json;;", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/things/42", nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusNotFound, w.Code)
+
+			if !tt.problem {
+				assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+				var resp models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, models.CodeNotFound, resp.Code)
+				assert.Equal(t, "Thing not found", resp.Error)
+				return
+			}
+
+			assert.Equal(t, apierror.ProblemMediaType, w.Header().Get("Content-Type"))
+			var problem models.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, models.Problem{
+				Type:      "urn:web-analyzer:problem:not_found",
+				Title:     "Thing not found",
+				Status:    http.StatusNotFound,
+				Detail:    "No thing has this id",
+				Instance:  "/things/42",
+				Code:      models.CodeNotFound,
+				RequestID: "req-1",
+			}, problem)
+		})
+	}
+}
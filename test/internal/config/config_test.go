@@ -0,0 +1,201 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/config"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestDefaults(t *testing.T) {
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.Default(), cfg)
+	assert.Equal(t, 30*time.Second, time.Duration(cfg.Analysis.Timeout))
+	assert.Equal(t, 10, cfg.Analysis.MaxRedirects)
+	assert.Equal(t, "WebAnalyzer/1.0", cfg.Analysis.UserAgent)
+	assert.Equal(t, 10*time.Second, time.Duration(cfg.Server.ShutdownTimeout))
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Run("YAML", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", `
+server:
+  port: 9090
+  cors_origins: ["https://dashboard.example.com"]
+analysis:
+  timeout: 45s
+  link_timeout: 2s
+  user_agent: TestAgent/2.0
+network:
+  allow_cidrs: ["10.1.0.0/16"]
+  max_idle_conns_per_host: 64
+  disable_http2: true
+log:
+  level: debug
+`)
+		cfg, err := config.Load([]string{"-config", path})
+		require.NoError(t, err)
+
+		assert.Equal(t, 9090, cfg.Server.Port)
+		assert.Equal(t, []string{"https://dashboard.example.com"}, cfg.Server.CORSOrigins)
+		assert.Equal(t, 45*time.Second, time.Duration(cfg.Analysis.Timeout))
+		assert.Equal(t, 2*time.Second, time.Duration(cfg.Analysis.LinkTimeout))
+		assert.Equal(t, "TestAgent/2.0", cfg.Analysis.UserAgent)
+		assert.Equal(t, []string{"10.1.0.0/16"}, cfg.Network.AllowCIDRs)
+		assert.Equal(t, 64, cfg.Network.MaxIdleConnsPerHost)
+		assert.True(t, cfg.Network.DisableHTTP2)
+		assert.Equal(t, "debug", cfg.Log.Level)
+		// Settings missing from the file keep their defaults.
+		assert.Equal(t, 10*time.Second, time.Duration(cfg.Analysis.FetchTimeout))
+	})
+
+	t.Run("TOML", func(t *testing.T) {
+		path := writeFile(t, "config.toml", `
+[server]
+port = 9191
+shutdown_timeout = "20s"
+
+[analysis]
+workers = 3
+link_buffer = 10
+`)
+		cfg, err := config.Load([]string{"-config", path})
+		require.NoError(t, err)
+
+		assert.Equal(t, 9191, cfg.Server.Port)
+		assert.Equal(t, 20*time.Second, time.Duration(cfg.Server.ShutdownTimeout))
+		assert.Equal(t, 3, cfg.Analysis.Workers)
+		assert.Equal(t, 10, cfg.Analysis.LinkBuffer)
+	})
+
+	t.Run("Unknown Key", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "analysis:\n  timout: 5s\n")
+		_, err := config.Load([]string{"-config", path})
+		assert.ErrorContains(t, err, "timout")
+	})
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		path := writeFile(t, "config.json", "{}")
+		_, err := config.Load([]string{"-config", path})
+		assert.ErrorContains(t, err, "unsupported config file format")
+	})
+}
+
+func TestPrecedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+analysis:
+  max_links: 100
+  workers: 4
+`)
+	t.Setenv("WEB_ANALYZER_CONFIG", path)
+	t.Setenv("WEB_ANALYZER_MAX_LINKS", "200")
+	t.Setenv("WEB_ANALYZER_LINK_WORKERS", "6")
+	t.Setenv("WEB_ANALYZER_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
+	t.Setenv("WEB_ANALYZER_DISABLE_HTTP2", "false")
+
+	cfg, err := config.Load([]string{"-link-workers", "8", "-disable-http2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9000, cfg.Server.Port, "file beats defaults")
+	assert.Equal(t, 200, cfg.Analysis.MaxLinks, "env beats file")
+	assert.Equal(t, 8, cfg.Analysis.Workers, "flag beats env")
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, cfg.Server.TrustedProxies)
+	assert.True(t, cfg.Network.DisableHTTP2, "a bare boolean flag beats env")
+}
+
+func TestInvalidEnv(t *testing.T) {
+	t.Setenv("WEB_ANALYZER_LINK_TIMEOUT", "soon")
+
+	_, err := config.Load(nil)
+	assert.ErrorContains(t, err, "WEB_ANALYZER_LINK_TIMEOUT")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.Port = 0
+	cfg.Server.GRPCPort = 70000
+	cfg.Server.CORSOrigins = []string{"dashboard.example.com"}
+	cfg.Analysis.Workers = 0
+	cfg.Analysis.Timeout = config.Duration(-time.Second)
+	cfg.Network.DenyCIDRs = []string{"not-a-cidr"}
+	cfg.Network.MaxIdleConnsPerHost = 0
+	cfg.Network.DialTimeout = 0
+	cfg.Log.Level = "verbose"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, msg := range []string{"server.port", "server.grpc_port", "server.cors_origins", "analysis.workers",
+		"analysis.timeout", "invalid deny list", "network.max_idle_conns_per_host", "network.dial_timeout", "log.level"} {
+		assert.ErrorContains(t, err, msg)
+	}
+
+	_, err = config.Load([]string{"-max-redirects", "-1"})
+	assert.ErrorContains(t, err, "analysis.max_redirects")
+}
+
+func TestRestartRequired(t *testing.T) {
+	current := config.Default()
+
+	next := config.Default()
+	next.Analysis.UserAgent = "Other/1.0"
+	next.Log.Level = "debug"
+	assert.Empty(t, current.RestartRequired(next))
+
+	next.Server.Port = 9999
+	next.Server.CORSOrigins = []string{"https://a.example"}
+	assert.Equal(t, []string{"server.port", "server.cors_origins"}, current.RestartRequired(next))
+}
+
+func TestReloader(t *testing.T) {
+	var applied []*config.Config
+	fail := false
+	reloader := config.NewReloader(config.Default(), func(cfg *config.Config) error {
+		if fail {
+			return errors.New("invalid deny list")
+		}
+		applied = append(applied, cfg)
+		return nil
+	})
+
+	first := config.Default()
+	first.Server.Port = 9999
+	first.Log.Level = "debug"
+	pending, err := reloader.Reload(first)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server.port"}, pending)
+
+	// The second reload is compared with the first, not with the startup configuration.
+	second := config.Default()
+	second.Server.Port = 9999
+	second.Server.CORSOrigins = []string{"https://a.example"}
+	pending, err = reloader.Reload(second)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server.cors_origins"}, pending)
+	assert.Equal(t, []*config.Config{first, second}, applied)
+
+	fail = true
+	_, err = reloader.Reload(config.Default())
+	require.Error(t, err)
+
+	fail = false
+	pending, err = reloader.Reload(second)
+	require.NoError(t, err)
+	assert.Empty(t, pending, "a failed reload leaves the previous configuration current")
+}
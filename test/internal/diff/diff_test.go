@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/diff"
+	"web-analyzer/internal/models"
+)
+
+func records() (*models.AnalysisRecord, *models.AnalysisRecord) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	before := &models.AnalysisRecord{
+		ID: "before", URL: "https://example.com", CreatedAt: created,
+		Analysis: &models.PageAnalysis{
+			Title:       "Shop",
+			HTMLVersion: "HTML 4.01 Strict",
+			Headings:    map[string]int{"h1": 1, "h2": 3},
+			MetaTags:    map[string]string{"description": "old", "keywords": "shop"},
+			LinksStatus: map[string]string{
+				"https://example.com/a":       "OK",
+				"https://example.com/b":       "OK",
+				"https://example.com/removed": "OK",
+				"https://example.com/flaky":   "Status: 503 Service Unavailable",
+			},
+			InternalLinks: 4,
+			BrokenLinks:   1,
+		},
+	}
+	after := &models.AnalysisRecord{
+		ID: "after", URL: "https://example.com", CreatedAt: created.Add(time.Hour),
+		Analysis: &models.PageAnalysis{
+			Title:        "Shop - Sale",
+			HTMLVersion:  "HTML5",
+			HasLoginForm: true,
+			Headings:     map[string]int{"h1": 1, "h2": 4},
+			MetaTags:     map[string]string{"description": "new", "og:title": "Sale"},
+			LinksStatus: map[string]string{
+				"https://example.com/a":     "OK",
+				"https://example.com/b":     "Status: 404 Not Found",
+				"https://example.com/flaky": "OK",
+				"https://example.com/new":   "Error: connection refused",
+				"https://example.com/added": "OK",
+			},
+			InternalLinks: 5,
+			BrokenLinks:   2,
+		},
+	}
+	return before, after
+}
+
+func TestCompare(t *testing.T) {
+	before, after := records()
+	r := diff.Compare(before, after)
+
+	assert.True(t, r.Changed)
+	assert.Equal(t, "https://example.com", r.URL)
+	assert.Equal(t, "before", r.From.ID)
+	assert.Equal(t, &diff.ValueChange{From: "Shop", To: "Shop - Sale"}, r.Title)
+	assert.Equal(t, &diff.ValueChange{From: "HTML 4.01 Strict", To: "HTML5"}, r.HTMLVersion)
+	assert.Equal(t, &diff.LoginFormChange{Appeared: true}, r.LoginForm)
+
+	assert.Equal(t, map[string]string{"og:title": "Sale"}, r.MetaTags.Added)
+	assert.Equal(t, map[string]string{"keywords": "shop"}, r.MetaTags.Removed)
+	assert.Equal(t, map[string]diff.ValueChange{"description": {From: "old", To: "new"}}, r.MetaTags.Changed)
+
+	assert.Equal(t, []diff.HeadingChange{{Level: "h2", From: 3, To: 4}}, r.Headings)
+
+	assert.Equal(t, []string{"https://example.com/added", "https://example.com/new"}, r.Links.Added)
+	assert.Equal(t, []string{"https://example.com/removed"}, r.Links.Removed)
+	assert.Equal(t, []diff.LinkStatusChange{
+		{URL: "https://example.com/b", From: "OK", To: "Status: 404 Not Found"},
+		{URL: "https://example.com/new", To: "Error: connection refused"},
+	}, r.Links.NowBroken)
+	assert.Equal(t, []diff.LinkStatusChange{
+		{URL: "https://example.com/flaky", From: "Status: 503 Service Unavailable", To: "OK"},
+	}, r.Links.Fixed)
+	assert.Equal(t, diff.CountChange{From: 1, To: 2}, r.Links.Counts.Broken)
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	before, _ := records()
+	r := diff.Compare(before, before)
+
+	assert.False(t, r.Changed)
+	assert.Nil(t, r.Title)
+	assert.Empty(t, r.Links.Added)
+	assert.Contains(t, r.Text(), "No changes.")
+}
+
+func TestComparePartial(t *testing.T) {
+	t.Run("Skipped Links In From", func(t *testing.T) {
+		before, after := records()
+		// Only /a was checked before, e.g. with check_links=false or an include filter.
+		before.Analysis.LinksStatus = map[string]string{"https://example.com/a": "OK"}
+		before.Analysis.SkippedLinks = 3
+
+		r := diff.Compare(before, after)
+		assert.True(t, r.Links.Partial)
+		assert.Equal(t, "from skipped 3 link(s)", r.Links.PartialReason)
+		assert.Empty(t, r.Links.Added, "links From did not check are not added")
+		assert.Empty(t, r.Links.NowBroken)
+		assert.Empty(t, r.Links.Removed)
+		assert.Contains(t, r.Text(), "Links compared partially: from skipped 3 link(s)")
+	})
+
+	t.Run("Cancelled To", func(t *testing.T) {
+		before, after := records()
+		delete(after.Analysis.LinksStatus, "https://example.com/a")
+		after.Analysis.Cancelled = true
+
+		r := diff.Compare(before, after)
+		assert.True(t, r.Links.Partial)
+		assert.Empty(t, r.Links.Removed, "links To did not check are not removed")
+		assert.Equal(t, []string{"https://example.com/added", "https://example.com/new"}, r.Links.Added)
+		assert.Len(t, r.Links.NowBroken, 2)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		before, after := records()
+		after.Analysis.Truncate("page exceeded 2 links")
+
+		r := diff.Compare(before, after)
+		assert.Equal(t, "to was truncated: page exceeded 2 links", r.Links.PartialReason)
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		before, after := records()
+		assert.False(t, diff.Compare(before, after).Links.Partial)
+	})
+}
+
+func TestText(t *testing.T) {
+	before, after := records()
+	text := diff.Compare(before, after).Text()
+
+	require.Contains(t, text, "Changes for https://example.com\n")
+	for _, line := range []string{
+		`Title: "Shop" -> "Shop - Sale"`,
+		"HTML version: HTML 4.01 Strict -> HTML5",
+		"Login form: appeared",
+		`  + og:title: "Sale"`,
+		`  - keywords: "shop"`,
+		`  ~ description: "old" -> "new"`,
+		"  h2: 3 -> 4",
+		"broken 1 -> 2",
+		"  ! https://example.com/b now broken: Status: 404 Not Found",
+		"  * https://example.com/flaky fixed: OK",
+		"  + https://example.com/added",
+		"  - https://example.com/removed",
+	} {
+		assert.Contains(t, text, line)
+	}
+}
@@ -0,0 +1,227 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/export"
+	"web-analyzer/internal/models"
+)
+
+func sampleReport() export.Report {
+	return export.Report{
+		URL:        "https://example.com/",
+		ResultID:   "result-1",
+		AnalyzedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Analysis: &models.PageAnalysis{
+			HTMLVersion:   "HTML5",
+			Title:         `Example <Domain>`,
+			Headings:      map[string]int{"h1": 1, "h2": 3},
+			InternalLinks: 2,
+			ExternalLinks: 2,
+			BrokenLinks:   2,
+			BlockedLinks:  1,
+			LoadTime:      1500,
+			LinksStatus: map[string]string{
+				"https://example.com/about":   "OK",
+				"https://example.com/missing": "Status: 404 Not Found",
+				"https://old.example.org/":    "Error: certificate expired on 2024-01-01",
+				"http://10.0.0.1/admin":       "Blocked: 10.0.0.1 is a private address",
+			},
+			MetaTags: map[string]string{"description": "An example"},
+			TLS:      &models.TLSInfo{Version: "TLS 1.3", SelfSigned: true},
+			MixedContent: models.MixedContentInfo{
+				Active: 1,
+				Items: []models.MixedContentItem{
+					{Element: "script", Attribute: "src", URL: "http://cdn.example.com/app.js", Type: models.MixedContentActive},
+				},
+			},
+		},
+	}
+}
+
+func render(t *testing.T, format export.Format, r export.Report) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, export.Write(&buf, format, r))
+	return buf.String()
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range export.Formats {
+		parsed, err := export.ParseFormat(strings.ToUpper(string(f)))
+		require.NoError(t, err)
+		assert.Equal(t, f, parsed)
+		assert.NotEmpty(t, f.MediaType())
+	}
+
+	_, err := export.ParseFormat("xlsx")
+	assert.ErrorContains(t, err, "json, csv, html, junit, sarif")
+}
+
+func TestJSON(t *testing.T) {
+	var analysis models.PageAnalysis
+	require.NoError(t, json.Unmarshal([]byte(render(t, export.JSON, sampleReport())), &analysis))
+	assert.Equal(t, "Example <Domain>", analysis.Title)
+}
+
+func TestCSV(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(render(t, export.CSV, sampleReport()))).ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"page_url", "link_url", "state", "status"},
+		{"https://example.com/", "http://10.0.0.1/admin", export.LinkBlocked, "Blocked: 10.0.0.1 is a private address"},
+		{"https://example.com/", "https://example.com/about", export.LinkOK, "OK"},
+		{"https://example.com/", "https://example.com/missing", export.LinkBroken, "Status: 404 Not Found"},
+		{"https://example.com/", "https://old.example.org/", export.LinkExpiredCertificate, "Error: certificate expired on 2024-01-01"},
+	}, rows)
+}
+
+func TestHTML(t *testing.T) {
+	out := render(t, export.HTML, sampleReport())
+
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(t, out, "Example &lt;Domain&gt;", "page content is escaped")
+	assert.Contains(t, out, "https://example.com/missing")
+	assert.Contains(t, out, "Status: 404 Not Found")
+	assert.Contains(t, out, "self-signed")
+	assert.NotContains(t, out, "<script src=", "the report loads nothing")
+	assert.NotContains(t, out, "<link ", "the report loads nothing")
+}
+
+type junitSuites struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Skipped  int `xml:"skipped,attr"`
+	Suites   []struct {
+		Name      string `xml:"name,attr"`
+		Timestamp string `xml:"timestamp,attr"`
+		Time      string `xml:"time,attr"`
+		Cases     []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Message string `xml:"message,attr"`
+				Type    string `xml:"type,attr"`
+			} `xml:"failure"`
+			Skipped *struct{} `xml:"skipped"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func TestJUnit(t *testing.T) {
+	var suites junitSuites
+	require.NoError(t, xml.Unmarshal([]byte(render(t, export.JUnit, sampleReport())), &suites))
+
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+	require.Len(t, suites.Suites, 1)
+	suite := suites.Suites[0]
+	assert.Equal(t, "https://example.com/", suite.Name)
+	assert.Equal(t, "2025-03-01T12:00:00", suite.Timestamp)
+	assert.Equal(t, "1.500", suite.Time)
+
+	failures := map[string]string{}
+	for _, tc := range suite.Cases {
+		if tc.Failure != nil {
+			failures[tc.Name] = tc.Failure.Message
+		}
+		if tc.Name == "http://10.0.0.1/admin" {
+			assert.NotNil(t, tc.Skipped)
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"https://example.com/missing": "Status: 404 Not Found",
+		"https://old.example.org/":    "Error: certificate expired on 2024-01-01",
+	}, failures)
+}
+
+func TestJUnitWithoutLinks(t *testing.T) {
+	r := sampleReport()
+	r.Analysis.LinksStatus = nil
+
+	var suites junitSuites
+	require.NoError(t, xml.Unmarshal([]byte(render(t, export.JUnit, r)), &suites))
+	assert.Zero(t, suites.Tests)
+	assert.Zero(t, suites.Failures)
+}
+
+type sarifLog struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Name  string `json:"name"`
+				Rules []struct {
+					ID string `json:"id"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			Level     string `json:"level"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			PartialFingerprints map[string]string `json:"partialFingerprints"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func TestSARIF(t *testing.T) {
+	var log sarifLog
+	require.NoError(t, json.Unmarshal([]byte(render(t, export.SARIF, sampleReport())), &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "web-analyzer", run.Tool.Driver.Name)
+
+	rules := map[string]bool{}
+	for _, rule := range run.Tool.Driver.Rules {
+		rules[rule.ID] = true
+	}
+	var results []string
+	fingerprints := map[string]bool{}
+	for _, result := range run.Results {
+		assert.True(t, rules[result.RuleID], "result rule %s is declared", result.RuleID)
+		require.Len(t, result.Locations, 1)
+		assert.Equal(t, "https://example.com/", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		fingerprint := result.PartialFingerprints["webAnalyzerFinding/v1"]
+		assert.NotEmpty(t, fingerprint)
+		assert.False(t, fingerprints[fingerprint], "fingerprints are unique")
+		fingerprints[fingerprint] = true
+		results = append(results, result.RuleID+":"+result.Level)
+	}
+	assert.Equal(t, []string{
+		export.RuleBrokenLink + ":error",
+		export.RuleLinkCertificate + ":error",
+		export.RulePageCertificate + ":error",
+		export.RuleMixedContent + ":error",
+	}, results)
+
+	t.Run("Stable Fingerprints", func(t *testing.T) {
+		var again sarifLog
+		require.NoError(t, json.Unmarshal([]byte(render(t, export.SARIF, sampleReport())), &again))
+		assert.Equal(t, log.Runs[0].Results[0].PartialFingerprints, again.Runs[0].Results[0].PartialFingerprints)
+	})
+
+	t.Run("Clean Page", func(t *testing.T) {
+		r := export.Report{URL: "https://example.com/", Analysis: &models.PageAnalysis{LinksStatus: map[string]string{"https://example.com/a": "OK"}}}
+		out := render(t, export.SARIF, r)
+		assert.Contains(t, out, `"results": []`, "a clean page still has an empty results array")
+	})
+}
@@ -0,0 +1,338 @@
+package grpcserver_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/grpcserver"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/netguard"
+	"web-analyzer/internal/ratelimit"
+	"web-analyzer/internal/utils"
+	"web-analyzer/pkg/analyzerpb"
+	"web-analyzer/test/internal/testnet"
+)
+
+// dial starts srv on an in-memory listener and returns a connection to it.
+func dial(t *testing.T, srv *grpcserver.Server) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// testSite serves a page with one working and one broken link, reachable through the
+// network guard for the duration of the test.
+func testSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	testnet.AllowLoopbackFor(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/about":
+			_, _ = w.Write([]byte("about"))
+		default:
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>gRPC</title></head><body>` +
+				`<h1>Hello</h1><a href="/about">about</a><a href="/missing">gone</a></body></html>`))
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestAnalyzerService(t *testing.T) {
+	site := testSite(t)
+	client := analyzerpb.NewAnalyzerServiceClient(dial(t, grpcserver.New(grpcserver.Options{MaxBatchSize: 3})))
+	ctx := context.Background()
+
+	t.Run("Analyze", func(t *testing.T) {
+		var header metadata.MD
+		resp, err := client.Analyze(ctx, &analyzerpb.AnalyzeRequest{Url: site.URL}, grpc.Header(&header))
+		require.NoError(t, err)
+
+		page := resp.GetAnalysis()
+		assert.Equal(t, "gRPC", page.GetTitle())
+		assert.Equal(t, "HTML5", page.GetHtmlVersion())
+		assert.Equal(t, int32(1), page.GetHeadings()["h1"])
+		assert.Equal(t, int32(2), page.GetInternalLinks())
+		assert.Equal(t, int32(1), page.GetBrokenLinks())
+		assert.Equal(t, "OK", page.GetLinksStatus()[site.URL+"/about"])
+		assert.Equal(t, int32(http.StatusOK), page.GetStatusCode())
+		assert.NotEmpty(t, page.GetHeaders()["Content-Type"])
+		assert.NotEmpty(t, header.Get("x-request-id"))
+	})
+
+	t.Run("Invalid Request", func(t *testing.T) {
+		_, err := client.Analyze(ctx, &analyzerpb.AnalyzeRequest{
+			Url:         site.URL,
+			LinkTimeout: durationpb.New(time.Millisecond),
+			Sections:    []string{"colors"},
+		})
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+
+		var fields []string
+		for _, detail := range st.Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, v := range badRequest.GetFieldViolations() {
+					fields = append(fields, v.GetField())
+				}
+			}
+		}
+		assert.ElementsMatch(t, []string{"sections", "link_timeout"}, fields)
+
+		_, err = client.Analyze(ctx, &analyzerpb.AnalyzeRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Blocked Destination", func(t *testing.T) {
+		previous := utils.NetworkGuard()
+		utils.SetNetworkGuard(netguard.Default())
+		defer utils.SetNetworkGuard(previous)
+
+		_, err := client.Analyze(ctx, &analyzerpb.AnalyzeRequest{Url: site.URL})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Unreachable Page", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		_, err := client.Analyze(ctx, &analyzerpb.AnalyzeRequest{Url: closed.URL})
+		st := status.Convert(err)
+		assert.Equal(t, codes.FailedPrecondition, st.Code())
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, models.CodeUpstreamUnreachable, info.GetReason())
+	})
+
+	t.Run("Analyze Stream", func(t *testing.T) {
+		stream, err := client.AnalyzeStream(ctx, &analyzerpb.AnalyzeRequest{Url: site.URL})
+		require.NoError(t, err)
+
+		states := map[string]analyzerpb.LinkState{}
+		var page *analyzerpb.PageAnalysis
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			require.Nil(t, page, "the analysis is the last message")
+			if link := msg.GetLink(); link != nil {
+				states[link.GetUrl()] = link.GetState()
+			}
+			page = msg.GetAnalysis()
+		}
+
+		assert.Equal(t, map[string]analyzerpb.LinkState{
+			site.URL + "/about":   analyzerpb.LinkState_LINK_STATE_OK,
+			site.URL + "/missing": analyzerpb.LinkState_LINK_STATE_BROKEN,
+		}, states)
+		require.NotNil(t, page)
+		assert.Equal(t, int32(1), page.GetBrokenLinks())
+	})
+
+	t.Run("Batch Analyze", func(t *testing.T) {
+		resp, err := client.BatchAnalyze(ctx, &analyzerpb.BatchAnalyzeRequest{Requests: []*analyzerpb.AnalyzeRequest{
+			{Url: site.URL},
+			{Url: "ftp://example.com"},
+			{Url: site.URL + "/about"},
+		}})
+		require.NoError(t, err)
+		require.Len(t, resp.GetResults(), 3)
+
+		assert.Equal(t, "gRPC", resp.GetResults()[0].GetAnalysis().GetTitle())
+		assert.Equal(t, "ftp://example.com", resp.GetResults()[1].GetUrl())
+		assert.Equal(t, int32(codes.InvalidArgument), resp.GetResults()[1].GetError().GetCode())
+		assert.NotNil(t, resp.GetResults()[2].GetAnalysis())
+	})
+
+	t.Run("Batch Size", func(t *testing.T) {
+		_, err := client.BatchAnalyze(ctx, &analyzerpb.BatchAnalyzeRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		requests := make([]*analyzerpb.AnalyzeRequest, 4)
+		for i := range requests {
+			requests[i] = &analyzerpb.AnalyzeRequest{Url: site.URL}
+		}
+		_, err = client.BatchAnalyze(ctx, &analyzerpb.BatchAnalyzeRequest{Requests: requests})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestHealthAndReflection(t *testing.T) {
+	conn := dial(t, grpcserver.New(grpcserver.Options{}))
+	ctx := context.Background()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: analyzerpb.AnalyzerService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	reply, err := stream.Recv()
+	require.NoError(t, err)
+
+	var services []string
+	for _, s := range reply.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	assert.Contains(t, services, analyzerpb.AnalyzerService_ServiceDesc.ServiceName)
+	assert.Contains(t, services, healthpb.Health_ServiceDesc.ServiceName)
+}
+
+func TestAuthentication(t *testing.T) {
+	site := testSite(t)
+	authenticator := auth.New(&auth.Config{Keys: []auth.APIKey{{ID: "platform", Key: "static-key"}}})
+	conn := dial(t, grpcserver.New(grpcserver.Options{Auth: authenticator}))
+	client := analyzerpb.NewAnalyzerServiceClient(conn)
+	req := &analyzerpb.AnalyzeRequest{Url: site.URL}
+
+	_, err := client.Analyze(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.Analyze(metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "wrong"), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	for _, md := range [][]string{{"x-api-key", "static-key"}, {"authorization", "Bearer static-key"}} {
+		_, err = client.Analyze(metadata.AppendToOutgoingContext(context.Background(), md...), req)
+		assert.NoError(t, err, md[0])
+	}
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err, "health checks need no credentials")
+}
+
+func TestPerKeyLimits(t *testing.T) {
+	authenticator := auth.New(&auth.Config{Keys: []auth.APIKey{
+		{ID: "bursty", Key: "bursty-key", RateLimit: 1, Burst: 2},
+		{ID: "metered", Key: "metered-key", DailyQuota: 3},
+	}})
+	client := analyzerpb.NewAnalyzerServiceClient(dial(t, grpcserver.New(grpcserver.Options{Auth: authenticator})))
+	// Invalid requests still count: the limits apply before the request is validated.
+	invalid := &analyzerpb.AnalyzeRequest{}
+	reason := func(err error) string {
+		for _, detail := range status.Convert(err).Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				return info.Reason
+			}
+		}
+		return ""
+	}
+
+	t.Run("Rate Limit", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "bursty-key")
+		var got []codes.Code
+		var err error
+		for i := 0; i < 3; i++ {
+			_, err = client.Analyze(ctx, invalid)
+			got = append(got, status.Code(err))
+		}
+		assert.Equal(t, []codes.Code{codes.InvalidArgument, codes.InvalidArgument, codes.ResourceExhausted}, got)
+		assert.Equal(t, models.CodeRateLimited, reason(err))
+
+		var retry *errdetails.RetryInfo
+		for _, detail := range status.Convert(err).Details() {
+			if r, ok := detail.(*errdetails.RetryInfo); ok {
+				retry = r
+			}
+		}
+		require.NotNil(t, retry)
+		assert.Positive(t, retry.GetRetryDelay().AsDuration())
+	})
+
+	t.Run("Daily Quota", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "metered-key")
+		batch := func(n int) error {
+			req := &analyzerpb.BatchAnalyzeRequest{}
+			for i := 0; i < n; i++ {
+				req.Requests = append(req.Requests, invalid)
+			}
+			_, err := client.BatchAnalyze(ctx, req)
+			return err
+		}
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(batch(21)), "an oversized batch is rejected without counting")
+		assert.NoError(t, batch(2))
+
+		err := batch(2)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err), "each page of a batch counts")
+		assert.Equal(t, models.CodeQuotaExceeded, reason(err))
+
+		_, err = client.Analyze(ctx, invalid)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "the rejected batch counted nothing")
+
+		stream, err := client.AnalyzeStream(ctx, invalid)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
+
+func TestClientRateLimit(t *testing.T) {
+	conn := dial(t, grpcserver.New(grpcserver.Options{
+		RateLimiter: ratelimit.NewMemory(ratelimit.Policy{Rate: 0.01, Burst: 2}),
+	}))
+	client := analyzerpb.NewAnalyzerServiceClient(conn)
+
+	var got []codes.Code
+	var err error
+	for i := 0; i < 3; i++ {
+		_, err = client.Analyze(context.Background(), &analyzerpb.AnalyzeRequest{})
+		got = append(got, status.Code(err))
+	}
+	assert.Equal(t, []codes.Code{codes.InvalidArgument, codes.InvalidArgument, codes.ResourceExhausted}, got,
+		"calls without a key are throttled per peer address")
+
+	var info *errdetails.ErrorInfo
+	for _, detail := range status.Convert(err).Details() {
+		if i, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+	require.NotNil(t, info)
+	assert.Equal(t, models.CodeRateLimited, info.GetReason())
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err, "health checks are not throttled")
+}
@@ -0,0 +1,81 @@
+package netguard_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/netguard"
+)
+
+func TestGuardCheck(t *testing.T) {
+	guard := netguard.Default()
+
+	testCases := []struct {
+		name    string
+		addr    string
+		blocked bool
+	}{
+		{"Public IPv4", "93.184.216.34", false},
+		{"Public IPv6", "2606:2800:220:1:248:1893:25c8:1946", false},
+		{"Loopback", "127.0.0.1", true},
+		{"Metadata Endpoint", "169.254.169.254", true},
+		{"RFC1918 10/8", "10.1.2.3", true},
+		{"RFC1918 172.16/12", "172.20.0.1", true},
+		{"RFC1918 192.168/16", "192.168.1.1", true},
+		{"CGNAT", "100.100.100.200", true},
+		{"Unspecified", "0.0.0.0", true},
+		{"IPv6 Loopback", "::1", true},
+		{"IPv6 Unique Local", "fd00:ec2::254", true},
+		{"IPv6 Link Local", "fe80::1", true},
+		{"IPv4-Mapped Loopback", "::ffff:127.0.0.1", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := guard.Check(netip.MustParseAddr(tc.addr))
+			if tc.blocked {
+				assert.ErrorIs(t, err, netguard.ErrBlocked)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGuardAllowDenyLists(t *testing.T) {
+	guard, err := netguard.New([]string{"10.20.0.0/16", "127.0.0.1"}, []string{"93.184.216.0/24", "10.20.30.40"})
+	require.NoError(t, err)
+
+	assert.NoError(t, guard.Check(netip.MustParseAddr("10.20.1.1")))
+	assert.NoError(t, guard.Check(netip.MustParseAddr("127.0.0.1")))
+	assert.ErrorIs(t, guard.Check(netip.MustParseAddr("127.0.0.2")), netguard.ErrBlocked)
+	assert.ErrorIs(t, guard.Check(netip.MustParseAddr("10.21.0.1")), netguard.ErrBlocked)
+	assert.ErrorIs(t, guard.Check(netip.MustParseAddr("93.184.216.34")), netguard.ErrBlocked)
+
+	// The deny list wins over the allow list.
+	assert.ErrorIs(t, guard.Check(netip.MustParseAddr("10.20.30.40")), netguard.ErrBlocked)
+
+	_, err = netguard.New([]string{"not-a-cidr"}, nil)
+	assert.Error(t, err)
+}
+
+func TestGuardDialer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	dialer := netguard.Default().Dialer(time.Second)
+	_, err = dialer.DialContext(context.Background(), "tcp", ln.Addr().String())
+	require.Error(t, err)
+
+	var blockedErr *netguard.BlockedError
+	assert.True(t, errors.As(err, &blockedErr))
+	assert.Equal(t, "127.0.0.1", blockedErr.Addr.String())
+}
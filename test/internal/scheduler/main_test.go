@@ -0,0 +1,14 @@
+package scheduler_test
+
+import (
+	"os"
+	"testing"
+
+	"web-analyzer/test/internal/testnet"
+)
+
+// Alert webhooks go through the outbound network guard; allow the loopback test receivers.
+func TestMain(m *testing.M) {
+	testnet.AllowLoopback()
+	os.Exit(m.Run())
+}
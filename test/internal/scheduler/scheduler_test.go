@@ -0,0 +1,450 @@
+package scheduler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/scheduler"
+	"web-analyzer/internal/storage"
+	"web-analyzer/internal/webhook"
+)
+
+const secret = "s3cret"
+
+// fakeSite serves canned analyses so runs need no network access.
+type fakeSite struct {
+	mu    sync.Mutex
+	pages []*models.PageAnalysis
+	err   error
+	calls int
+}
+
+func (f *fakeSite) analyze(_ context.Context, _ string) (*models.PageAnalysis, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	page := f.pages[0]
+	if len(f.pages) > 1 {
+		f.pages = f.pages[1:]
+	}
+	return page, nil
+}
+
+// alertReceiver is a local webhook endpoint that verifies and records alert payloads.
+type alertReceiver struct {
+	mu       sync.Mutex
+	payloads []scheduler.AlertPayload
+	invalid  int
+}
+
+func (rc *alertReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if webhook.Verify(secret, r.Header, body, time.Now(), time.Minute) != nil {
+		rc.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var payload scheduler.AlertPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rc.payloads = append(rc.payloads, payload)
+}
+
+func newScheduler(t *testing.T, site *fakeSite, store storage.Store, jobsPath string) *scheduler.Scheduler {
+	sender := webhook.NewSender(secret, webhook.RetryPolicy{MaxAttempts: 2}, 5*time.Second)
+	sender.Sleep = func(context.Context, time.Duration) error { return nil }
+
+	s, err := scheduler.New(scheduler.Options{
+		Analyze:  site.analyze,
+		Store:    store,
+		Sender:   sender,
+		JobsPath: jobsPath,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestAddValidation(t *testing.T) {
+	s := newScheduler(t, &fakeSite{}, nil, "")
+
+	tests := []struct {
+		name  string
+		spec  scheduler.JobSpec
+		field string
+	}{
+		{"Missing URL", scheduler.JobSpec{Interval: "1h"}, "url"},
+		{"Unsupported Scheme", scheduler.JobSpec{URL: "ftp://example.com", Interval: "1h"}, "url"},
+		{"No Schedule", scheduler.JobSpec{URL: "https://example.com"}, "cron"},
+		{"Both Schedules", scheduler.JobSpec{URL: "https://example.com", Cron: "@daily", Interval: "1h"}, "interval"},
+		{"Interval Too Short", scheduler.JobSpec{URL: "https://example.com", Interval: "10s"}, "interval"},
+		{"Invalid Cron", scheduler.JobSpec{URL: "https://example.com", Cron: "61 * * * *"}, "cron"},
+		{"Cron Never Matches", scheduler.JobSpec{URL: "https://example.com", Cron: "0 0 30 feb *"}, "cron"},
+		{"Unknown Timezone", scheduler.JobSpec{URL: "https://example.com", Cron: "@daily", Timezone: "Mars/Olympus"}, "timezone"},
+		{"Unknown Rule", scheduler.JobSpec{URL: "https://example.com", Interval: "1h", Rules: []scheduler.Rule{{Type: "nope"}}}, "rules[0]"},
+		{"Unknown Threshold Field", scheduler.JobSpec{URL: "https://example.com", Interval: "1h",
+			Rules: []scheduler.Rule{{Type: scheduler.RuleThreshold, Field: "nope", Op: ">"}}}, "rules[0]"},
+		{"Invalid Webhook", scheduler.JobSpec{URL: "https://example.com", Interval: "1h", WebhookURL: "not a url"}, "webhook_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Add(tt.spec, "")
+			var validationErr *scheduler.ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tt.field, validationErr.Fields[0].Field)
+		})
+	}
+	assert.Empty(t, s.List())
+}
+
+func TestCronNextRun(t *testing.T) {
+	s := newScheduler(t, &fakeSite{}, nil, "")
+
+	tests := []struct {
+		name     string
+		spec     scheduler.JobSpec
+		location string
+		check    func(t *testing.T, next time.Time)
+	}{
+		{"Weekday Mornings", scheduler.JobSpec{Cron: "30 9 * * mon-fri"}, "UTC", func(t *testing.T, next time.Time) {
+			assert.Equal(t, 9, next.Hour())
+			assert.Equal(t, 30, next.Minute())
+			assert.NotContains(t, []time.Weekday{time.Saturday, time.Sunday}, next.Weekday())
+		}},
+		{"Every Ten Minutes", scheduler.JobSpec{Cron: "*/10 * * * *"}, "UTC", func(t *testing.T, next time.Time) {
+			assert.Zero(t, next.Minute()%10)
+			assert.LessOrEqual(t, time.Until(next), 10*time.Minute)
+		}},
+		{"Monthly Macro", scheduler.JobSpec{Cron: "@monthly"}, "UTC", func(t *testing.T, next time.Time) {
+			assert.Equal(t, 1, next.Day())
+			assert.Equal(t, 0, next.Hour())
+		}},
+		{"Timezone", scheduler.JobSpec{Cron: "0 8 * * *", Timezone: "Asia/Colombo"}, "Asia/Colombo", func(t *testing.T, next time.Time) {
+			assert.Equal(t, 8, next.Hour())
+			assert.Equal(t, 0, next.Minute())
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.URL = "https://example.com"
+			job, err := s.Add(tt.spec, "")
+			require.NoError(t, err)
+
+			loc, err := time.LoadLocation(tt.location)
+			require.NoError(t, err)
+			assert.True(t, job.NextRun.After(time.Now()))
+			assert.LessOrEqual(t, time.Until(job.NextRun), 32*24*time.Hour)
+			tt.check(t, job.NextRun.In(loc))
+		})
+	}
+
+	t.Run("Interval", func(t *testing.T) {
+		job, err := s.Add(scheduler.JobSpec{URL: "https://example.com", Interval: "15m"}, "")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), job.NextRun, 5*time.Second)
+	})
+}
+
+func TestRunNowAlerts(t *testing.T) {
+	rc := &alertReceiver{}
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	site := &fakeSite{pages: []*models.PageAnalysis{
+		{Title: "Shop", HasLoginForm: true, LinksStatus: map[string]string{"https://example.com/a": "OK"}},
+		{Title: "", BrokenLinks: 1, LinksStatus: map[string]string{"https://example.com/a": "Status: 404 Not Found"}},
+	}}
+	store := storage.NewMemoryStore(storage.Retention{})
+	s := newScheduler(t, site, store, "")
+
+	job, err := s.Add(scheduler.JobSpec{
+		URL:      "https://example.com",
+		Interval: "1h",
+		Rules: []scheduler.Rule{
+			{Type: scheduler.RuleThreshold, Field: "broken_links", Op: ">", Value: 0},
+			{Type: scheduler.RuleTitleMissing},
+			{Type: scheduler.RuleLoginFormDisappeared},
+			{Type: scheduler.RuleNewBrokenLinks},
+		},
+		WebhookURL: ts.URL,
+	}, "")
+	require.NoError(t, err)
+
+	t.Run("Healthy Run Raises Nothing", func(t *testing.T) {
+		run, err := s.RunNow(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Empty(t, run.Error)
+		assert.Empty(t, run.Alerts)
+		assert.Nil(t, run.Webhook)
+		require.NotEmpty(t, run.ResultID)
+
+		record, err := store.Get(context.Background(), run.ResultID)
+		require.NoError(t, err)
+		assert.Equal(t, "schedule:"+job.ID, record.RequestID)
+	})
+
+	t.Run("Regression Raises Signed Alert", func(t *testing.T) {
+		run, err := s.RunNow(context.Background(), job.ID)
+		require.NoError(t, err)
+
+		var rules []string
+		for _, alert := range run.Alerts {
+			rules = append(rules, alert.Rule)
+		}
+		assert.Equal(t, []string{"broken_links > 0", "title_missing", "login_form_disappeared", "new_broken_links"}, rules)
+
+		require.NotNil(t, run.Webhook)
+		assert.Equal(t, webhook.StatusDelivered, run.Webhook.Status)
+		assert.Equal(t, scheduler.EventAlert, run.Webhook.Event)
+
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		assert.Zero(t, rc.invalid)
+		require.Len(t, rc.payloads, 1)
+		payload := rc.payloads[0]
+		assert.Equal(t, job.ID, payload.ScheduleID)
+		assert.Equal(t, run.ResultID, payload.ResultID)
+		assert.Len(t, payload.Alerts, 4)
+		require.NotNil(t, payload.Analysis)
+		assert.Equal(t, 1, payload.Analysis.BrokenLinks)
+	})
+
+	t.Run("Job Records Last Run", func(t *testing.T) {
+		stored, err := s.Get(job.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.LastRun)
+		assert.Len(t, stored.LastRun.Alerts, 4)
+		assert.Equal(t, stored.LastRun.ResultID, stored.LastResultID)
+	})
+}
+
+func TestRunNowTLSThreshold(t *testing.T) {
+	ts := httptest.NewServer(&alertReceiver{})
+	defer ts.Close()
+
+	site := &fakeSite{pages: []*models.PageAnalysis{
+		{Title: "Plain HTTP"},
+		{Title: "Expiring", TLS: &models.TLSInfo{DaysRemaining: 5}},
+	}}
+	s := newScheduler(t, site, storage.NewMemoryStore(storage.Retention{}), "")
+
+	job, err := s.Add(scheduler.JobSpec{
+		URL:        "https://example.com",
+		Interval:   "1h",
+		Rules:      []scheduler.Rule{{Type: scheduler.RuleThreshold, Field: "tls_days_remaining", Op: "<", Value: 14}},
+		WebhookURL: ts.URL,
+	}, "")
+	require.NoError(t, err)
+
+	run, err := s.RunNow(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Empty(t, run.Alerts, "a result without TLS data skips the rule")
+
+	run, err = s.RunNow(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Len(t, run.Alerts, 1)
+	assert.Equal(t, "tls_days_remaining is 5", run.Alerts[0].Message)
+}
+
+func TestRunNowFailure(t *testing.T) {
+	rc := &alertReceiver{}
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	site := &fakeSite{err: errors.New("connection refused")}
+	s := newScheduler(t, site, nil, "")
+
+	job, err := s.Add(scheduler.JobSpec{
+		URL:        "https://example.com",
+		Interval:   "1h",
+		Rules:      []scheduler.Rule{{Type: scheduler.RuleAnalysisFailed}, {Type: scheduler.RuleTitleMissing}},
+		WebhookURL: ts.URL,
+	}, "")
+	require.NoError(t, err)
+
+	run, err := s.RunNow(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "connection refused", run.Error)
+	require.Len(t, run.Alerts, 1)
+	assert.Equal(t, scheduler.RuleAnalysisFailed, run.Alerts[0].Rule)
+	assert.Contains(t, run.Alerts[0].Message, "connection refused")
+	require.Len(t, rc.payloads, 1)
+	assert.Nil(t, rc.payloads[0].Analysis)
+
+	_, err = s.RunNow(context.Background(), "unknown")
+	assert.ErrorIs(t, err, scheduler.ErrJobNotFound)
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	site := &fakeSite{pages: []*models.PageAnalysis{{Title: "Home"}}}
+
+	s := newScheduler(t, site, nil, path)
+	kept, err := s.Add(scheduler.JobSpec{URL: "https://example.com", Cron: "@hourly"}, "")
+	require.NoError(t, err)
+	removed, err := s.Add(scheduler.JobSpec{URL: "https://example.org", Interval: "2h"}, "")
+	require.NoError(t, err)
+	require.NoError(t, s.Remove(removed.ID))
+	assert.ErrorIs(t, s.Remove(removed.ID), scheduler.ErrJobNotFound)
+
+	reopened := newScheduler(t, site, nil, path)
+	jobs := reopened.List()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, kept.ID, jobs[0].ID)
+	assert.Equal(t, "@hourly", jobs[0].Cron)
+	assert.True(t, kept.NextRun.Equal(jobs[0].NextRun))
+}
+
+func TestRunStartsDueJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+
+	// A job whose run was missed while the server was down runs as soon as the scheduler starts.
+	stored := []scheduler.Job{{
+		ID:        "missed",
+		JobSpec:   scheduler.JobSpec{URL: "https://example.com", Interval: "1h"},
+		CreatedAt: time.Now().Add(-3 * time.Hour).UTC(),
+		NextRun:   time.Now().Add(-2 * time.Hour).UTC(),
+	}}
+	data, err := json.Marshal(stored)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	site := &fakeSite{pages: []*models.PageAnalysis{{Title: "Home"}}}
+	s := newScheduler(t, site, nil, path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		job, err := s.Get("missed")
+		return err == nil && job.LastRun != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	job, err := s.Get("missed")
+	require.NoError(t, err)
+	assert.True(t, job.NextRun.After(time.Now()), "missed runs are coalesced into one")
+
+	site.mu.Lock()
+	assert.Equal(t, 1, site.calls)
+	site.mu.Unlock()
+}
+
+func TestRunsChargeQuota(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	stored := []scheduler.Job{{
+		ID:        "metered",
+		JobSpec:   scheduler.JobSpec{URL: "https://example.com", Interval: "1h"},
+		CreatedAt: time.Now().Add(-3 * time.Hour).UTC(),
+		NextRun:   time.Now().Add(-2 * time.Hour).UTC(),
+		KeyID:     "ci",
+	}}
+	data, err := json.Marshal(stored)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	errQuota := errors.New("daily quota exceeded")
+	var mu sync.Mutex
+	var charged []string
+	site := &fakeSite{pages: []*models.PageAnalysis{{Title: "Home"}}}
+	s, err := scheduler.New(scheduler.Options{
+		Analyze:  site.analyze,
+		JobsPath: path,
+		ChargeQuota: func(keyID string, n int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			charged = append(charged, keyID)
+			if len(charged) > 1 {
+				return errQuota
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = s.RunNow(context.Background(), "metered")
+	require.NoError(t, err)
+	_, err = s.RunNow(context.Background(), "metered")
+	assert.ErrorIs(t, err, errQuota, "RunNow reports the refusal")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(charged) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		job, err := s.Get("metered")
+		return err == nil && job.LastRun != nil && job.LastRun.Error != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, []string{"ci", "ci", "ci"}, charged)
+	site.mu.Lock()
+	assert.Equal(t, 1, site.calls, "refused runs do not analyze the page")
+	site.mu.Unlock()
+
+	unowned, err := s.Add(scheduler.JobSpec{URL: "https://example.com", Interval: "1h"}, "")
+	require.NoError(t, err)
+	_, err = s.RunNow(context.Background(), unowned.ID)
+	assert.NoError(t, err, "jobs created without a key are not charged")
+}
+
+func TestNewBrokenLinksIgnoresUncheckedLinks(t *testing.T) {
+	// The first run skipped /b, so /b being broken in the second is not a regression
+	// the schedule can vouch for.
+	site := &fakeSite{pages: []*models.PageAnalysis{
+		{LinksStatus: map[string]string{"https://example.com/a": "OK"}, SkippedLinks: 1},
+		{BrokenLinks: 1, LinksStatus: map[string]string{
+			"https://example.com/a": "OK",
+			"https://example.com/b": "Status: 404 Not Found",
+		}},
+	}}
+	s := newScheduler(t, site, storage.NewMemoryStore(storage.Retention{}), "")
+
+	job, err := s.Add(scheduler.JobSpec{
+		URL:      "https://example.com",
+		Interval: "1h",
+		Rules:    []scheduler.Rule{{Type: scheduler.RuleNewBrokenLinks}},
+	}, "")
+	require.NoError(t, err)
+
+	for range 2 {
+		run, err := s.RunNow(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Empty(t, run.Alerts)
+	}
+}
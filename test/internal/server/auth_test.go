@@ -0,0 +1,242 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/server"
+	"web-analyzer/pkg/api"
+	"web-analyzer/pkg/metrics"
+)
+
+const authConfigJSON = `{
+	"keys": [
+		{"id": "dashboard", "key": "static-key"},
+		{"id": "ci", "secret": "signing-secret"},
+		{"id": "bursty", "key": "bursty-key", "rate_limit": 1, "burst": 2},
+		{"id": "metered", "key": "metered-key", "daily_quota": 2}
+	]
+}`
+
+func newAuthRouter(t *testing.T, origins ...string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(authConfigJSON), 0o600))
+
+	cfg, err := auth.LoadConfig(path)
+	require.NoError(t, err)
+
+	return server.SetupRouterWithOptions(server.Options{
+		Auth:        auth.New(cfg),
+		CORSOrigins: origins,
+	})
+}
+
+func serve(router *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthentication(t *testing.T) {
+	router := newAuthRouter(t)
+
+	t.Run("Open Routes", func(t *testing.T) {
+		w := serve(router, httptest.NewRequest("GET", "/health", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Missing Key", func(t *testing.T) {
+		w := serve(router, httptest.NewRequest("GET", "/api/v1/analyze", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "missing API key")
+
+		w = serve(router, httptest.NewRequest("GET", "/url_analyze", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid Key", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+		req.Header.Set("X-API-Key", "wrong")
+		w := serve(router, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Static Key Header", func(t *testing.T) {
+		before := testutil.ToFloat64(metrics.APIKeyRequests.WithLabelValues("dashboard", "allowed"))
+
+		req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+		req.Header.Set("X-API-Key", "static-key")
+		w := serve(router, req)
+
+		// Authenticated, so the handler itself rejects the missing url parameter.
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.APIKeyRequests.WithLabelValues("dashboard", "allowed")))
+	})
+
+	t.Run("Bearer Token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+		req.Header.Set("Authorization", "Bearer static-key")
+		w := serve(router, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSignedRequests(t *testing.T) {
+	router := newAuthRouter(t)
+
+	t.Run("Valid Signature", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze?url=", nil)
+		require.NoError(t, api.SignRequest(req, "ci", "signing-secret", time.Now()))
+
+		w := serve(router, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Wrong Secret", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+		require.NoError(t, api.SignRequest(req, "ci", "not-the-secret", time.Now()))
+
+		w := serve(router, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid request signature")
+	})
+
+	t.Run("Tampered Query", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze?url=https://a.example", nil)
+		require.NoError(t, api.SignRequest(req, "ci", "signing-secret", time.Now()))
+		req.URL.RawQuery = "url=https://b.example"
+
+		w := serve(router, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Stale Timestamp", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+		require.NoError(t, api.SignRequest(req, "ci", "signing-secret", time.Now().Add(-time.Hour)))
+
+		w := serve(router, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "timestamp")
+	})
+
+	t.Run("Replayed Request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze?url=", nil)
+		require.NoError(t, api.SignRequest(req, "ci", "signing-secret", time.Now()))
+		replay := req.Clone(req.Context())
+
+		assert.Equal(t, http.StatusBadRequest, serve(router, req).Code)
+		w := serve(router, replay)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "nonce already used")
+	})
+
+	t.Run("Missing Nonce", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+		require.NoError(t, api.SignRequest(req, "ci", "signing-secret", time.Now()))
+		req.Header.Del(api.HeaderNonce)
+
+		w := serve(router, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "X-Nonce")
+	})
+
+	t.Run("Oversized Body", func(t *testing.T) {
+		// Only public headers are needed to make the server read the body.
+		body := &countingReader{Reader: strings.NewReader(strings.Repeat("a", 10<<20))}
+		req := httptest.NewRequest("POST", "/api/v1/analyze", body)
+		req.Header.Set("X-Key-ID", "ci")
+		req.Header.Set("X-Timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+		req.Header.Set("X-Nonce", "fresh")
+		req.Header.Set("X-Signature", "forged")
+
+		w := serve(router, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), models.CodeTooLarge)
+		assert.LessOrEqual(t, body.n, int64(analysis.MaxRequestBodyBytes+64<<10), "the body is not read past the limit")
+	})
+}
+
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func TestPerKeyLimits(t *testing.T) {
+	router := newAuthRouter(t)
+
+	t.Run("Rate Limit", func(t *testing.T) {
+		var codes []int
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+			req.Header.Set("X-API-Key", "bursty-key")
+			w := serve(router, req)
+			codes = append(codes, w.Code)
+			if w.Code == http.StatusTooManyRequests {
+				assert.NotEmpty(t, w.Header().Get("Retry-After"))
+			}
+		}
+		assert.Equal(t, []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusTooManyRequests}, codes)
+	})
+
+	t.Run("Daily Quota", func(t *testing.T) {
+		var last *httptest.ResponseRecorder
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+			req.Header.Set("X-API-Key", "metered-key")
+			last = serve(router, req)
+		}
+		assert.Equal(t, http.StatusTooManyRequests, last.Code)
+		assert.Contains(t, last.Body.String(), "Daily quota exceeded")
+		assert.Equal(t, "0", last.Header().Get("X-Quota-Remaining"))
+	})
+}
+
+func TestCORSAllowlist(t *testing.T) {
+	router := newAuthRouter(t, "https://dashboard.example.com")
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("OPTIONS", "/api/v1/analyze", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		return serve(router, req)
+	}
+
+	w := preflight("https://dashboard.example.com")
+	assert.Equal(t, "https://dashboard.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+	w = preflight("https://evil.example.com")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestLoadAuthConfigValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(strings.ReplaceAll(authConfigJSON, `"id": "ci"`, `"id": "dashboard"`)), 0o600))
+
+	_, err := auth.LoadConfig(path)
+	assert.ErrorContains(t, err, "duplicate key id")
+}
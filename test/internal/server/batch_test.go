@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/server"
+)
+
+func batchRequest(body, apiKey string) *http.Request {
+	req := httptest.NewRequest("POST", "/api/v1/analyze/batch", strings.NewReader(body))
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	return req
+}
+
+func TestBatchAnalyze(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := server.SetupRouter()
+
+	t.Run("Per Request Results", func(t *testing.T) {
+		w := serve(router, batchRequest(`{"requests": [{"url": ""}, {"url": "ftp://example.com"}, {"url": "https://example.com", "callback_url": "https://hooks.example.com"}]}`, ""))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp models.BatchAnalyzeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Results, 3)
+		assert.Equal(t, "ftp://example.com", resp.Results[1].URL)
+		for _, result := range resp.Results {
+			assert.Nil(t, result.Analysis)
+			require.NotNil(t, result.Error)
+			assert.Equal(t, models.CodeInvalidInput, result.Error.Code)
+		}
+		assert.Equal(t, "callback_url", resp.Results[2].Error.Fields[0].Field)
+	})
+
+	t.Run("Batch Size", func(t *testing.T) {
+		w := serve(router, batchRequest(`{"requests": []}`, ""))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"requests"`)
+
+		w = serve(router, batchRequest(`{"requests": [`+strings.Repeat(`{"url": ""},`, 20)+`{"url": ""}]}`, ""))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "got 21")
+	})
+
+	t.Run("Invalid Body", func(t *testing.T) {
+		w := serve(router, batchRequest(`{"urls": ["https://example.com"]}`, ""))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"body"`)
+
+		w = serve(router, batchRequest(`{"requests": [{"url": "`+strings.Repeat("a", 70<<10)+`"}]}`, ""))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestBatchAnalyzeQuota(t *testing.T) {
+	router := newAuthRouter(t)
+
+	w := serve(router, batchRequest(`{"requests": [{"url": ""}, {"url": ""}, {"url": ""}]}`, "metered-key"))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "a batch larger than the quota is rejected whole")
+	assert.Equal(t, "2", w.Header().Get("X-Quota-Remaining"))
+
+	w = serve(router, batchRequest(`{"requests": [{"url": ""}, {"url": ""}]}`, "metered-key"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-Quota-Remaining"))
+
+	req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+	req.Header.Set("X-API-Key", "metered-key")
+	w = serve(router, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "every request of the batch was counted")
+}
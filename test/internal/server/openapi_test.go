@@ -0,0 +1,424 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/diff"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/scheduler"
+	"web-analyzer/internal/server"
+	"web-analyzer/internal/storage"
+	"web-analyzer/internal/webhook"
+	"web-analyzer/test/internal/testnet"
+)
+
+// schemaTypes maps the spec's component schemas to the Go types the handlers encode.
+var schemaTypes = map[string]reflect.Type{
+	"PageAnalysis":         reflect.TypeFor[models.PageAnalysis](),
+	"MixedContentInfo":     reflect.TypeFor[models.MixedContentInfo](),
+	"MixedContentItem":     reflect.TypeFor[models.MixedContentItem](),
+	"TLSInfo":              reflect.TypeFor[models.TLSInfo](),
+	"CertificateInfo":      reflect.TypeFor[models.CertificateInfo](),
+	"AnalyzeRequest":       reflect.TypeFor[models.AnalyzeRequest](),
+	"BatchAnalyzeRequest":  reflect.TypeFor[models.BatchAnalyzeRequest](),
+	"BatchAnalyzeResponse": reflect.TypeFor[models.BatchAnalyzeResponse](),
+	"BatchResult":          reflect.TypeFor[models.BatchResult](),
+	"CallbackAccepted":     reflect.TypeFor[models.CallbackAccepted](),
+	"CallbackPayload":      reflect.TypeFor[models.CallbackPayload](),
+	"ErrorResponse":        reflect.TypeFor[models.ErrorResponse](),
+	"Problem":              reflect.TypeFor[models.Problem](),
+	"FieldError":           reflect.TypeFor[models.FieldError](),
+	"AnalysisRecord":       reflect.TypeFor[models.AnalysisRecord](),
+	"HistoryResponse":      reflect.TypeFor[models.HistoryResponse](),
+	"DiffReport":           reflect.TypeFor[diff.Report](),
+	"Snapshot":             reflect.TypeFor[diff.Snapshot](),
+	"ValueChange":          reflect.TypeFor[diff.ValueChange](),
+	"LoginFormChange":      reflect.TypeFor[diff.LoginFormChange](),
+	"MetaTagChanges":       reflect.TypeFor[diff.MetaTagChanges](),
+	"HeadingChange":        reflect.TypeFor[diff.HeadingChange](),
+	"LinkChanges":          reflect.TypeFor[diff.LinkChanges](),
+	"LinkStatusChange":     reflect.TypeFor[diff.LinkStatusChange](),
+	"LinkCounts":           reflect.TypeFor[diff.LinkCounts](),
+	"CountChange":          reflect.TypeFor[diff.CountChange](),
+	"JobSpec":              reflect.TypeFor[scheduler.JobSpec](),
+	"Job":                  reflect.TypeFor[scheduler.Job](),
+	"Rule":                 reflect.TypeFor[scheduler.Rule](),
+	"Run":                  reflect.TypeFor[scheduler.Run](),
+	"Alert":                reflect.TypeFor[scheduler.Alert](),
+	"AlertPayload":         reflect.TypeFor[scheduler.AlertPayload](),
+	"Delivery":             reflect.TypeFor[webhook.Delivery](),
+	"DeliveryAttempt":      reflect.TypeFor[webhook.Attempt](),
+}
+
+// spec is the decoded OpenAPI document with just enough helpers to check it.
+type spec struct {
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components struct {
+		Schemas   map[string]map[string]any `json:"schemas"`
+		Responses map[string]map[string]any `json:"responses"`
+	} `json:"components"`
+}
+
+type operation struct {
+	Responses map[string]map[string]any `json:"responses"`
+}
+
+func loadSpec(t *testing.T) *spec {
+	t.Helper()
+	var s spec
+	require.NoError(t, json.Unmarshal(server.OpenAPISpec(), &s))
+	return &s
+}
+
+// resolve follows a local $ref.
+func (s *spec) resolve(node map[string]any) map[string]any {
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node
+	}
+	parts := strings.Split(strings.TrimPrefix(ref, "#/components/"), "/")
+	switch parts[0] {
+	case "schemas":
+		return s.resolve(s.Components.Schemas[parts[1]])
+	case "responses":
+		return s.resolve(s.Components.Responses[parts[1]])
+	}
+	return nil
+}
+
+// properties merges the properties and required names of an object schema and its allOf parts.
+func (s *spec) properties(schema map[string]any) (map[string]map[string]any, []string) {
+	schema = s.resolve(schema)
+	props := map[string]map[string]any{}
+	var required []string
+	for _, part := range asSlice(schema["allOf"]) {
+		p, r := s.properties(part.(map[string]any))
+		for name, prop := range p {
+			props[name] = prop
+		}
+		required = append(required, r...)
+	}
+	for name, prop := range asMap(schema["properties"]) {
+		props[name] = prop.(map[string]any)
+	}
+	for _, name := range asSlice(schema["required"]) {
+		required = append(required, name.(string))
+	}
+	return props, required
+}
+
+// validate checks value, decoded from JSON, against schema and returns the problems found.
+func (s *spec) validate(where string, schema map[string]any, value any) []string {
+	schema = s.resolve(schema)
+	if allOf := asSlice(schema["allOf"]); allOf != nil {
+		var problems []string
+		for _, part := range allOf {
+			problems = append(problems, s.validate(where, part.(map[string]any), value)...)
+		}
+		return problems
+	}
+	if value == nil {
+		if schema["nullable"] == true || schema["type"] == nil {
+			return nil
+		}
+		return []string{where + ": null"}
+	}
+	if enum := asSlice(schema["enum"]); enum != nil && !slices.Contains(enum, value) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", where, value, enum)}
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return []string{where + ": not an object"}
+		}
+		var problems []string
+		props, required := s.properties(schema)
+		for _, name := range required {
+			if _, ok := obj[name]; !ok {
+				problems = append(problems, where+"."+name+": required but missing")
+			}
+		}
+		for name, v := range obj {
+			if prop, ok := props[name]; ok {
+				problems = append(problems, s.validate(where+"."+name, prop, v)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+				problems = append(problems, s.validate(where+"."+name, additional, v)...)
+			}
+		}
+		return problems
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return []string{where + ": not an array"}
+		}
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, s.validate(fmt.Sprintf("%s[%d]", where, i), asMap(schema["items"]), item)...)
+		}
+		return problems
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{where + ": not a string"}
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return []string{where + ": not a date-time"}
+			}
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			return []string{where + ": not an integer"}
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return []string{where + ": not a number"}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{where + ": not a boolean"}
+		}
+	}
+	return nil
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	s := loadSpec(t)
+
+	sched, err := scheduler.New(scheduler.Options{Analyze: analysis.AnalyzePage})
+	require.NoError(t, err)
+	router := server.SetupRouterWithOptions(server.Options{Scheduler: sched, Deliveries: webhook.NewDeliveryLog(10)})
+
+	var routes, documented []string
+	for _, route := range router.Routes() {
+		routes = append(routes, route.Method+" "+strings.ReplaceAll(route.Path, ":id", "{id}"))
+	}
+	for path, item := range s.Paths {
+		for method := range item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(routes)
+	sort.Strings(documented)
+	assert.Equal(t, routes, documented, "every route is documented and every documented route exists")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, string(server.OpenAPISpec()), w.Body.String())
+}
+
+func TestOpenAPISchemasMatchTypes(t *testing.T) {
+	s := loadSpec(t)
+
+	for name := range s.Components.Schemas {
+		if name == "Health" { // gin.H
+			continue
+		}
+		assert.Contains(t, schemaTypes, name, "schema %s has no Go type", name)
+	}
+
+	for name, typ := range schemaTypes {
+		t.Run(name, func(t *testing.T) {
+			schema, ok := s.Components.Schemas[name]
+			require.True(t, ok, "no schema for %s", typ)
+
+			props, required := s.properties(schema)
+			fields := jsonFields(typ)
+
+			var fieldNames, wantRequired []string
+			for _, f := range fields {
+				fieldNames = append(fieldNames, f.name)
+				if !f.omitempty {
+					wantRequired = append(wantRequired, f.name)
+				}
+				if prop, ok := props[f.name]; ok {
+					assert.NoError(t, typeMatches(s, f.typ, prop), "%s.%s", name, f.name)
+				}
+			}
+			propNames := make([]string, 0, len(props))
+			for prop := range props {
+				propNames = append(propNames, prop)
+			}
+			assert.ElementsMatch(t, fieldNames, propNames, "properties of %s", name)
+			assert.ElementsMatch(t, wantRequired, required, "required properties of %s", name)
+		})
+	}
+}
+
+type jsonField struct {
+	name      string
+	omitempty bool
+	typ       reflect.Type
+}
+
+// jsonFields lists the fields encoding/json writes for typ, flattening embedded structs.
+func jsonFields(typ reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			fields = append(fields, jsonFields(f.Type)...)
+			continue
+		}
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{name: name, omitempty: strings.Contains(opts, "omitempty"), typ: f.Type})
+	}
+	return fields
+}
+
+func typeMatches(s *spec, typ reflect.Type, prop map[string]any) error {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if _, ok := prop["$ref"]; ok {
+		if typ.Kind() != reflect.Struct {
+			return fmt.Errorf("%s is documented as an object reference", typ)
+		}
+		return nil
+	}
+
+	want := map[reflect.Kind]string{
+		reflect.String: "string", reflect.Bool: "boolean",
+		reflect.Int: "integer", reflect.Int64: "integer",
+		reflect.Float64: "number", reflect.Slice: "array", reflect.Map: "object",
+	}[typ.Kind()]
+	if typ == reflect.TypeFor[time.Time]() {
+		want = "string"
+		if prop["format"] != "date-time" {
+			return fmt.Errorf("time is documented without the date-time format")
+		}
+	}
+	if prop["type"] != want {
+		return fmt.Errorf("%s is documented as %v, want %s", typ, prop["type"], want)
+	}
+	switch typ.Kind() {
+	case reflect.Slice:
+		return typeMatches(s, typ.Elem(), asMap(prop["items"]))
+	case reflect.Map:
+		return typeMatches(s, typ.Elem(), asMap(prop["additionalProperties"]))
+	}
+	return nil
+}
+
+func TestOpenAPIResponses(t *testing.T) {
+	s := loadSpec(t)
+
+	testnet.AllowLoopbackFor(t)
+
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Spec</title><meta name="description" content="d"></head>
+			<body><h1>Spec</h1><a href="/missing">gone</a><a href="/">home</a></body></html>`))
+	}))
+	defer site.Close()
+
+	analysis.SetResultStore(storage.NewMemoryStore(storage.Retention{}))
+	defer analysis.SetResultStore(nil)
+	sched, err := scheduler.New(scheduler.Options{Analyze: analysis.AnalyzePage})
+	require.NoError(t, err)
+	router := server.SetupRouterWithOptions(server.Options{Scheduler: sched, Deliveries: webhook.NewDeliveryLog(10)})
+
+	// check serves a request and validates the JSON response against the documented
+	// response of the operation for its status code.
+	check := func(method, path, specPath, body string, wantStatus int) map[string]any {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		require.Equal(t, wantStatus, w.Code, "%s %s: %s", method, path, w.Body.String())
+
+		op, ok := s.Paths[specPath][strings.ToLower(method)]
+		require.True(t, ok, "%s %s is not documented", method, specPath)
+		response, ok := op.Responses[strconv.Itoa(w.Code)]
+		require.True(t, ok, "%s %s: status %d is not documented", method, specPath, w.Code)
+		response = s.resolve(response)
+
+		content := asMap(response["content"])
+		if w.Body.Len() == 0 {
+			assert.Empty(t, content, "%s %s: documented body missing", method, specPath)
+			return nil
+		}
+		schema := asMap(asMap(content["application/json"])["schema"])
+		require.NotNil(t, schema, "%s %s: no JSON schema for %d", method, specPath, w.Code)
+
+		var decoded any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &decoded))
+		assert.Empty(t, s.validate(method+" "+specPath, schema, decoded))
+		obj, _ := decoded.(map[string]any)
+		return obj
+	}
+
+	check("GET", "/health", "/health", "", http.StatusOK)
+
+	pageURL := url.QueryEscape(site.URL)
+	first := check("GET", "/api/v1/analyze?url="+pageURL, "/api/v1/analyze", "", http.StatusOK)
+	second := check("POST", "/api/v1/analyze", "/api/v1/analyze",
+		`{"url": "`+site.URL+`", "sections": ["title", "links"]}`, http.StatusOK)
+	check("POST", "/api/v1/analyze", "/api/v1/analyze", `{"url": "`+site.URL+`", "sections": ["nope"]}`, http.StatusBadRequest)
+	check("GET", "/url_analyze?url="+pageURL, "/url_analyze", "", http.StatusOK)
+	check("POST", "/api/v1/analyze/batch", "/api/v1/analyze/batch",
+		`{"requests": [{"url": "`+site.URL+`"}, {"url": "http://127.0.0.1:1/"}, {"url": ""}]}`, http.StatusOK)
+	check("POST", "/api/v1/analyze/batch", "/api/v1/analyze/batch", `{"requests": []}`, http.StatusBadRequest)
+	check("GET", "/api/v1/analyze?url="+url.QueryEscape("http://127.0.0.1:1/"), "/api/v1/analyze", "", http.StatusUnprocessableEntity)
+
+	firstID, secondID := first["result_id"].(string), second["result_id"].(string)
+	require.NotEmpty(t, firstID)
+	check("GET", "/api/v1/results/"+firstID, "/api/v1/results/{id}", "", http.StatusOK)
+	check("GET", "/api/v1/results/unknown", "/api/v1/results/{id}", "", http.StatusNotFound)
+	check("GET", "/api/v1/history?url="+pageURL, "/api/v1/history", "", http.StatusOK)
+	check("GET", "/api/v1/history", "/api/v1/history", "", http.StatusBadRequest)
+	check("GET", "/api/v1/diff?from="+firstID+"&to="+secondID, "/api/v1/diff", "", http.StatusOK)
+
+	job := check("POST", "/api/v1/schedules", "/api/v1/schedules",
+		`{"url": "`+site.URL+`", "interval": "1h", "rules": [{"type": "threshold", "field": "broken_links", "op": ">", "value": 0}]}`,
+		http.StatusCreated)
+	jobPath := "/api/v1/schedules/" + job["id"].(string)
+	check("POST", "/api/v1/schedules", "/api/v1/schedules", `{"url": "`+site.URL+`"}`, http.StatusBadRequest)
+	check("POST", jobPath+"/run", "/api/v1/schedules/{id}/run", "", http.StatusOK)
+	check("GET", jobPath, "/api/v1/schedules/{id}", "", http.StatusOK)
+	check("GET", "/api/v1/schedules", "/api/v1/schedules", "", http.StatusOK)
+	check("DELETE", jobPath, "/api/v1/schedules/{id}", "", http.StatusNoContent)
+	check("GET", jobPath, "/api/v1/schedules/{id}", "", http.StatusNotFound)
+
+	check("GET", "/api/v1/webhooks/deliveries", "/api/v1/webhooks/deliveries", "", http.StatusOK)
+	check("GET", "/api/v1/webhooks/deliveries?limit=0", "/api/v1/webhooks/deliveries", "", http.StatusBadRequest)
+	check("GET", "/api/v1/webhooks/deliveries/unknown", "/api/v1/webhooks/deliveries/{id}", "", http.StatusNotFound)
+}
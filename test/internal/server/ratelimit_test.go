@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"web-analyzer/internal/ratelimit"
+	"web-analyzer/internal/server"
+)
+
+func newRateLimitedRouter(limiter ratelimit.Limiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return server.SetupRouterWithOptions(server.Options{
+		RateLimiters: map[string]ratelimit.Limiter{server.RouteGroupAPI: limiter},
+	})
+}
+
+func requestFrom(router *gin.Engine, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	router := newRateLimitedRouter(ratelimit.NewMemory(ratelimit.Policy{Rate: 0.5, Burst: 2}))
+
+	t.Run("Burst Then Throttle", func(t *testing.T) {
+		first := requestFrom(router, "192.0.2.1:1234")
+		assert.Equal(t, http.StatusBadRequest, first.Code)
+		assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+
+		second := requestFrom(router, "192.0.2.1:1234")
+		assert.Equal(t, http.StatusBadRequest, second.Code)
+		assert.Equal(t, "0", second.Header().Get("X-RateLimit-Remaining"))
+
+		third := requestFrom(router, "192.0.2.1:1234")
+		assert.Equal(t, http.StatusTooManyRequests, third.Code)
+		assert.Contains(t, third.Body.String(), "Rate limit exceeded")
+
+		retryAfter, err := strconv.Atoi(third.Header().Get("Retry-After"))
+		assert.NoError(t, err)
+		assert.InDelta(t, 2, retryAfter, 1)
+
+		reset, err := strconv.Atoi(third.Header().Get("X-RateLimit-Reset"))
+		assert.NoError(t, err)
+		assert.Greater(t, reset, 0)
+	})
+
+	t.Run("Separate Clients", func(t *testing.T) {
+		w := requestFrom(router, "192.0.2.2:1234")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Spoofed Forwarded For Is Ignored", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/analyze", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Forwarded-For", "198.51.100.7")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("Other Groups Unaffected", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	})
+}
+
+type recordingLimiter struct {
+	keys []string
+	err  error
+}
+
+func (l *recordingLimiter) Allow(_ context.Context, key string) (ratelimit.Result, error) {
+	l.keys = append(l.keys, key)
+	return ratelimit.Result{Allowed: l.err == nil, Limit: 1}, l.err
+}
+
+func TestRateLimiterInterface(t *testing.T) {
+	t.Run("Keyed By Client IP", func(t *testing.T) {
+		limiter := &recordingLimiter{}
+		router := newRateLimitedRouter(limiter)
+
+		requestFrom(router, "203.0.113.9:5555")
+		assert.Equal(t, []string{"ip:203.0.113.9"}, limiter.keys)
+	})
+
+	t.Run("Store Failure Fails Open", func(t *testing.T) {
+		router := newRateLimitedRouter(&recordingLimiter{err: errors.New("store unavailable")})
+
+		w := requestFrom(router, "203.0.113.9:5555")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"web-analyzer/internal/server"
 )
@@ -28,6 +29,10 @@ func TestSetupRouter(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
+		resp, err = http.Post(ts.URL+"/api/v1/analyze", "application/json", strings.NewReader(`{"url": ""}`))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
 		resp, err = http.Get(ts.URL + "/health")
 		assert.NoError(t, err)
 	})
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/scheduler"
+	"web-analyzer/internal/server"
+)
+
+func TestScheduleRoutes(t *testing.T) {
+	sched, err := scheduler.New(scheduler.Options{
+		Analyze: func(context.Context, string) (*models.PageAnalysis, error) {
+			return &models.PageAnalysis{Title: ""}, nil
+		},
+	})
+	require.NoError(t, err)
+	router := server.SetupRouterWithOptions(server.Options{Scheduler: sched})
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := serve("POST", "/api/v1/schedules",
+		`{"url": "https://example.com", "interval": "30m", "rules": [{"type": "title_missing"}]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var job scheduler.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, "/api/v1/schedules/"+job.ID, w.Header().Get("Location"))
+
+	t.Run("Validation", func(t *testing.T) {
+		w := serve("POST", "/api/v1/schedules", `{"url": "https://example.com", "interval": "1s"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"interval"`)
+
+		w = serve("POST", "/api/v1/schedules", `{"url": "https://example.com", "every": "1h"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"body"`)
+
+		// No webhook sender is configured on this router.
+		w = serve("POST", "/api/v1/schedules", `{"url": "https://example.com", "interval": "1h", "webhook_url": "https://hooks.example.com"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"webhook_url"`)
+	})
+
+	t.Run("List And Get", func(t *testing.T) {
+		w := serve("GET", "/api/v1/schedules", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var list struct {
+			Schedules []scheduler.Job `json:"schedules"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Schedules, 1)
+		assert.Equal(t, job.ID, list.Schedules[0].ID)
+
+		assert.Equal(t, http.StatusOK, serve("GET", "/api/v1/schedules/"+job.ID, "").Code)
+		assert.Equal(t, http.StatusNotFound, serve("GET", "/api/v1/schedules/unknown", "").Code)
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		w := serve("POST", "/api/v1/schedules/"+job.ID+"/run", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var run scheduler.Run
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+		require.Len(t, run.Alerts, 1)
+		assert.Equal(t, scheduler.RuleTitleMissing, run.Alerts[0].Rule)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve("DELETE", "/api/v1/schedules/"+job.ID, "").Code)
+		assert.Equal(t, http.StatusNotFound, serve("DELETE", "/api/v1/schedules/"+job.ID, "").Code)
+	})
+}
+
+func TestScheduleRoutesDisabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	server.SetupRouter().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/schedules", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestScheduleRunsChargeQuota(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(authConfigJSON), 0o600))
+	cfg, err := auth.LoadConfig(path)
+	require.NoError(t, err)
+	authenticator := auth.New(cfg)
+
+	sched, err := scheduler.New(scheduler.Options{
+		Analyze: func(context.Context, string) (*models.PageAnalysis, error) {
+			return &models.PageAnalysis{Title: "Example"}, nil
+		},
+		ChargeQuota: func(keyID string, n int) error {
+			_, err := authenticator.ChargeQuota(keyID, n)
+			return err
+		},
+	})
+	require.NoError(t, err)
+	router := server.SetupRouterWithOptions(server.Options{Auth: authenticator, Scheduler: sched})
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", "metered-key")
+		return serve(router, req)
+	}
+
+	w := send("POST", "/api/v1/schedules", `{"url": "https://example.com", "interval": "1h"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var job scheduler.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, "metered", job.KeyID)
+
+	for range 2 {
+		assert.Equal(t, http.StatusOK, send("POST", "/api/v1/schedules/"+job.ID+"/run", "").Code)
+	}
+	w = send("POST", "/api/v1/schedules/"+job.ID+"/run", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"quota_exceeded"`)
+}
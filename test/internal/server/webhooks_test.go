@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/server"
+	"web-analyzer/internal/webhook"
+	"web-analyzer/pkg/api"
+)
+
+func TestDeliveryRoutes(t *testing.T) {
+	sender := webhook.NewSender("secret", webhook.RetryPolicy{MaxAttempts: 1}, time.Second)
+	sender.Log = webhook.NewDeliveryLog(10)
+	pending := sender.NewDelivery("https://hooks.example.com/a", "")
+	// The default network guard refuses loopback destinations, so this delivery fails.
+	failed, err := sender.Send(context.Background(), "http://127.0.0.1:1/b", "", "test.event", "payload")
+	require.Error(t, err)
+
+	router := server.SetupRouterWithOptions(server.Options{Deliveries: sender.Log})
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	w := get("/api/v1/webhooks/deliveries")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Deliveries []webhook.Delivery `json:"deliveries"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Deliveries, 2)
+	assert.Equal(t, failed.ID, list.Deliveries[0].ID)
+
+	w = get("/api/v1/webhooks/deliveries?status=pending")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Deliveries, 1)
+	assert.Equal(t, pending.ID, list.Deliveries[0].ID)
+
+	w = get("/api/v1/webhooks/deliveries/" + failed.ID)
+	require.Equal(t, http.StatusOK, w.Code)
+	var delivery webhook.Delivery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &delivery))
+	assert.Equal(t, webhook.StatusFailed, delivery.Status)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Contains(t, delivery.Attempts[0].Error, "blocked")
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/webhooks/deliveries/unknown").Code)
+
+	w = get("/api/v1/webhooks/deliveries?status=lost&limit=0")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"status"`)
+	assert.Contains(t, w.Body.String(), `"field":"limit"`)
+}
+
+func TestDeliveryRoutesScopedToKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(authConfigJSON), 0o600))
+	cfg, err := auth.LoadConfig(path)
+	require.NoError(t, err)
+
+	sender := webhook.NewSender("secret", webhook.RetryPolicy{MaxAttempts: 1}, time.Second)
+	sender.Log = webhook.NewDeliveryLog(10)
+	own := sender.NewDelivery("https://hooks.example.com/own", "dashboard")
+	other := sender.NewDelivery("https://hooks.example.com/other", "metered")
+
+	router := server.SetupRouterWithOptions(server.Options{Auth: auth.New(cfg), Deliveries: sender.Log})
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set(api.HeaderAPIKey, "static-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/webhooks/deliveries")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Deliveries []webhook.Delivery `json:"deliveries"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Deliveries, 1)
+	assert.Equal(t, own.ID, list.Deliveries[0].ID)
+	assert.NotContains(t, w.Body.String(), "hooks.example.com/other")
+
+	assert.Equal(t, http.StatusOK, get("/api/v1/webhooks/deliveries/"+own.ID).Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/webhooks/deliveries/"+other.ID).Code)
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/storage"
+)
+
+func record(id, url string, createdAt time.Time) *models.AnalysisRecord {
+	return &models.AnalysisRecord{
+		ID:        id,
+		URL:       url,
+		CreatedAt: createdAt,
+		Analysis:  &models.PageAnalysis{Title: "title " + id, Headings: map[string]int{"h1": 1}},
+	}
+}
+
+func ids(records []*models.AnalysisRecord) []string {
+	var out []string
+	for _, r := range records {
+		out = append(out, r.ID)
+	}
+	return out
+}
+
+// testStore runs the behaviour every Store implementation shares.
+func testStore(t *testing.T, open func(t *testing.T, retention storage.Retention) storage.Store) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("Save And Get", func(t *testing.T) {
+		store := open(t, storage.Retention{})
+		require.NoError(t, store.Save(ctx, record("a", "https://example.com", now)))
+
+		got, err := store.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "title a", got.Analysis.Title)
+
+		_, err = store.Get(ctx, "missing")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("History Pagination", func(t *testing.T) {
+		store := open(t, storage.Retention{})
+		for i := 0; i < 5; i++ {
+			require.NoError(t, store.Save(ctx, record(fmt.Sprint(i), "https://example.com", now.Add(time.Duration(i)*time.Minute))))
+		}
+		require.NoError(t, store.Save(ctx, record("other", "https://other.example", now)))
+
+		page, total, err := store.History(ctx, "https://example.com", storage.Page{Limit: 2, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 5, total)
+		assert.Equal(t, []string{"3", "2"}, ids(page), "newest first")
+
+		page, total, err = store.History(ctx, "https://example.com", storage.Page{Limit: 10, Offset: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 5, total)
+		assert.Empty(t, page)
+	})
+
+	t.Run("Retention Per URL", func(t *testing.T) {
+		store := open(t, storage.Retention{MaxPerURL: 2})
+		for i := 0; i < 4; i++ {
+			require.NoError(t, store.Save(ctx, record(fmt.Sprint(i), "https://example.com", now)))
+		}
+
+		page, total, err := store.History(ctx, "https://example.com", storage.Page{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []string{"3", "2"}, ids(page))
+
+		_, err = store.Get(ctx, "0")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("Retention Total", func(t *testing.T) {
+		store := open(t, storage.Retention{MaxRecords: 2})
+		for i := 0; i < 3; i++ {
+			require.NoError(t, store.Save(ctx, record(fmt.Sprint(i), fmt.Sprintf("https://%d.example", i), now)))
+		}
+
+		_, err := store.Get(ctx, "0")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = store.Get(ctx, "2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Retention Age", func(t *testing.T) {
+		store := open(t, storage.Retention{MaxAge: time.Hour})
+		require.NoError(t, store.Save(ctx, record("old", "https://example.com", now.Add(-2*time.Hour))))
+		require.NoError(t, store.Save(ctx, record("new", "https://example.com", now)))
+
+		_, err := store.Get(ctx, "old")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		page, total, err := store.History(ctx, "https://example.com", storage.Page{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []string{"new"}, ids(page))
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T, retention storage.Retention) storage.Store {
+		return storage.NewMemoryStore(retention)
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, func(t *testing.T, retention storage.Retention) storage.Store {
+		store, err := storage.OpenFileStore(filepath.Join(t.TempDir(), "results.jsonl"), retention)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	})
+}
+
+func TestFileStorePersistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	store, err := storage.OpenFileStore(path, storage.Retention{MaxPerURL: 2})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Save(ctx, record(fmt.Sprint(i), "https://example.com", now)))
+	}
+	require.NoError(t, store.Close())
+
+	// Simulate a crash in the middle of a write.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"put","record":{"id":"torn"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	store, err = storage.OpenFileStore(path, storage.Retention{MaxPerURL: 2})
+	require.NoError(t, err)
+	defer store.Close()
+
+	got, err := store.Get(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, now, got.CreatedAt)
+	assert.Equal(t, map[string]int{"h1": 1}, got.Analysis.Headings)
+
+	page, total, err := store.History(ctx, "https://example.com", storage.Page{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{"2", "1"}, ids(page))
+
+	// Reopening compacts away the evicted record and the torn entry.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+	assert.NotContains(t, string(data), "torn")
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+
+	store, err := storage.OpenFileStore(path, storage.Retention{MaxRecords: 1})
+	require.NoError(t, err)
+	defer store.Close()
+
+	for i := 0; i < 500; i++ {
+		require.NoError(t, store.Save(ctx, record(fmt.Sprint(i), "https://example.com", time.Now())))
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Less(t, strings.Count(string(data), "\n"), 300, "the log is compacted as evictions pile up")
+
+	got, err := store.Get(ctx, "499")
+	require.NoError(t, err)
+	assert.Equal(t, "title 499", got.Analysis.Title)
+}
+
+func TestFileStoreWriteFailures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Failed Save Is Not Indexed", func(t *testing.T) {
+		store, err := storage.OpenFileStore(filepath.Join(t.TempDir(), "results.jsonl"), storage.Retention{})
+		require.NoError(t, err)
+		require.NoError(t, store.Close())
+
+		assert.Error(t, store.Save(ctx, record("lost", "https://example.com", time.Now())))
+		_, err = store.Get(ctx, "lost")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("Failed Compaction Keeps The File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "results.jsonl")
+		store, err := storage.OpenFileStore(path, storage.Retention{MaxRecords: 1})
+		require.NoError(t, err)
+		defer store.Close()
+
+		// A directory in the way of the temporary file makes every compaction fail.
+		require.NoError(t, os.Mkdir(path+".tmp", 0o700))
+		for i := 0; i < 200; i++ {
+			require.NoError(t, store.Save(ctx, record(fmt.Sprint(i), "https://example.com", time.Now())))
+		}
+		require.NoError(t, os.Remove(path+".tmp"))
+		require.NoError(t, store.Close())
+
+		reopened, err := storage.OpenFileStore(path, storage.Retention{MaxRecords: 1})
+		require.NoError(t, err)
+		defer reopened.Close()
+		got, err := reopened.Get(ctx, "199")
+		require.NoError(t, err)
+		assert.Equal(t, "title 199", got.Analysis.Title)
+	})
+}
@@ -0,0 +1,33 @@
+// Package testnet sets up outbound networking for the test packages.
+package testnet
+
+import (
+	"testing"
+
+	"web-analyzer/internal/netguard"
+	"web-analyzer/internal/utils"
+)
+
+// LoopbackGuard returns the default network guard with the loopback ranges allowed, so
+// outbound requests can reach httptest servers.
+func LoopbackGuard() *netguard.Guard {
+	guard, err := netguard.New([]string{"127.0.0.0/8", "::1"}, nil)
+	if err != nil {
+		panic(err)
+	}
+	return guard
+}
+
+// AllowLoopback installs LoopbackGuard for the rest of the test binary. Call it from
+// TestMain.
+func AllowLoopback() {
+	utils.SetNetworkGuard(LoopbackGuard())
+}
+
+// AllowLoopbackFor installs LoopbackGuard until t and its subtests finish.
+func AllowLoopbackFor(t testing.TB) {
+	t.Helper()
+	previous := utils.NetworkGuard()
+	utils.SetNetworkGuard(LoopbackGuard())
+	t.Cleanup(func() { utils.SetNetworkGuard(previous) })
+}
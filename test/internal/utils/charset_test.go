@@ -0,0 +1,53 @@
+package utils_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+
+	"web-analyzer/internal/utils"
+)
+
+func TestDecodeCharset(t *testing.T) {
+	testCases := []struct {
+		name         string
+		enc          encoding.Encoding
+		prefix       string
+		text         string
+		contentType  string
+		expectedName string
+	}{
+		{"Shift_JIS From Header", japanese.ShiftJIS, "", "<title>日本語のページ</title>", "text/html; charset=Shift_JIS", "shift_jis"},
+		{"Windows-1252 From Meta", charmap.Windows1252, `<meta charset="windows-1252">`, "<title>Café – Déjà vu</title>", "text/html", "windows-1252"},
+		{"ISO-8859-2 From Http-Equiv", charmap.ISO8859_2, `<meta http-equiv="Content-Type" content="text/html; charset=iso-8859-2">`, "<title>Zażółć gęślą jaźń</title>", "text/html", "iso-8859-2"},
+		{"UTF-8 BOM", nil, "\xEF\xBB\xBF", "<title>Ünïcödé</title>", "text/html", "utf-8"},
+		{"UTF-8 Content Sniffing", nil, "", "<title>Привет, мир</title>", "text/html", "utf-8"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := []byte(tc.text)
+			if tc.enc != nil {
+				var err error
+				raw, err = tc.enc.NewEncoder().Bytes(raw)
+				require.NoError(t, err)
+			}
+
+			body := append([]byte(tc.prefix), raw...)
+			reader, name, err := utils.DecodeCharset(bytes.NewReader(body), tc.contentType)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, name)
+
+			decoded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Contains(t, string(decoded), tc.text)
+			assert.NotContains(t, string(decoded), "\uFEFF")
+		})
+	}
+}
@@ -1,12 +1,20 @@
 package utils_test
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
 
 	"web-analyzer/internal/utils"
 )
@@ -29,21 +37,106 @@ func TestDetectHTMLVersion(t *testing.T) {
 		{"XHTML Content-Type", `<html>`, "application/xhtml+xml", "XHTML (Content-Type based detection)"},
 		{"Non-standard DOCTYPE", `<!DOCTYPE html SYSTEM "about:legacy-compat">`, "text/html", "HTML (Non-standard DOCTYPE)"},
 		{"No DOCTYPE", `<html>`, "text/html", "HTML (No DOCTYPE)"},
+		{"DOCTYPE After Whitespace", "\n\n   <!DOCTYPE html>", "text/html", "HTML5"},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tc.doctype + "<html><body>Test</body></html>"))
+			require.NoError(t, err)
 
-			resp := &http.Response{
-				StatusCode: http.StatusOK,
-				Body:       io.NopCloser(strings.NewReader(tc.doctype + "<html><body>Test</body></html>")),
-				Header:     make(http.Header),
-			}
-			resp.Header.Set("Content-Type", tc.contentType)
-
-			// Detect HTML version
-			version := utils.DetectHTMLVersion(resp)
+			version := utils.DetectHTMLVersion(doc, tc.contentType)
 			assert.Equal(t, tc.expectedResult, version)
 		})
 	}
 }
+
+func TestDetectDocumentMode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		doctype  string
+		expected string
+	}{
+		{"HTML5", "<!DOCTYPE html>", utils.DocumentModeStandards},
+		{"Legacy Compat", `<!DOCTYPE html SYSTEM "about:legacy-compat">`, utils.DocumentModeStandards},
+		{"HTML 4.01 Strict", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01//EN" "http://www.w3.org/TR/html4/strict.dtd">`, utils.DocumentModeStandards},
+		{"HTML 4.01 Transitional With System ID", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN" "http://www.w3.org/TR/html4/loose.dtd">`, utils.DocumentModeLimitedQuirks},
+		{"HTML 4.01 Transitional Without System ID", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 4.01 Transitional//EN">`, utils.DocumentModeQuirks},
+		{"XHTML 1.0 Transitional", `<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">`, utils.DocumentModeLimitedQuirks},
+		{"HTML 3.2", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 3.2 Final//EN">`, utils.DocumentModeQuirks},
+		{"Non-HTML Name", `<!DOCTYPE svg>`, utils.DocumentModeQuirks},
+		{"No DOCTYPE", "", utils.DocumentModeQuirks},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tc.doctype + "<html><body>Test</body></html>"))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, utils.DetectDocumentMode(doc))
+		})
+	}
+}
+
+func TestDecodeContentEncoding(t *testing.T) {
+	const page = "<!DOCTYPE html><html><body>Compressed</body></html>"
+
+	compress := map[string]func(w io.Writer) io.WriteCloser{
+		"gzip": func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"br":   func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser {
+			return zlib.NewWriter(w)
+		},
+		"zstd": func(w io.Writer) io.WriteCloser {
+			zw, _ := zstd.NewWriter(w)
+			return zw
+		},
+		"raw-deflate": func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		},
+	}
+
+	for name, newWriter := range compress {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := newWriter(&buf)
+			_, err := w.Write([]byte(page))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			reader, err := utils.DecodeContentEncoding(&buf, strings.TrimPrefix(name, "raw-"))
+			require.NoError(t, err)
+
+			decoded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, page, string(decoded))
+		})
+	}
+
+	for _, encoding := range []string{"deflate", "gzip"} {
+		t.Run("Empty "+encoding, func(t *testing.T) {
+			reader, err := utils.DecodeContentEncoding(http.NoBody, encoding)
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Empty(t, decoded)
+		})
+	}
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := utils.DecodeContentEncoding(strings.NewReader(page), "compress")
+		assert.Error(t, err)
+	})
+
+	t.Run("Oversized zstd Window", func(t *testing.T) {
+		// A frame header asking for a 256 MiB window, under the library default, followed by an empty last block.
+		frame := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 18 << 3, 0x01, 0x00, 0x00}
+
+		reader, err := utils.DecodeContentEncoding(bytes.NewReader(frame), "zstd")
+		if err == nil {
+			_, err = io.ReadAll(reader)
+		}
+		assert.ErrorIs(t, err, zstd.ErrWindowSizeExceeded)
+	})
+}
@@ -0,0 +1,117 @@
+package utils_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/netguard"
+	"web-analyzer/internal/utils"
+	"web-analyzer/pkg/metrics"
+	"web-analyzer/test/internal/testnet"
+)
+
+// countingServer returns a server that counts the connections opened to it.
+func countingServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var conns atomic.Int64
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return ts, &conns
+}
+
+func get(t *testing.T, url string) error {
+	t.Helper()
+	resp, err := utils.NewHTTPClient(time.Second).Get(url)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+func TestHTTPClientReusesConnections(t *testing.T) {
+	ts, conns := countingServer(t)
+	reused := metrics.OutboundRequests.WithLabelValues("reused", "HTTP/1.1")
+	before := testutil.ToFloat64(reused)
+
+	for i := 0; i < 50; i++ {
+		require.NoError(t, get(t, ts.URL))
+	}
+
+	assert.Equal(t, int64(1), conns.Load(), "every client shares one pooled connection")
+	assert.Equal(t, float64(49), testutil.ToFloat64(reused)-before)
+}
+
+func TestHTTPClientConcurrentReuse(t *testing.T) {
+	ts, conns := countingServer(t)
+	const workers = 16
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				assert.NoError(t, get(t, ts.URL))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, conns.Load(), int64(workers), "idle connections are kept for every worker")
+}
+
+func TestSetNetworkGuardDropsPooledConnections(t *testing.T) {
+	ts, conns := countingServer(t)
+	require.NoError(t, get(t, ts.URL))
+
+	utils.SetNetworkGuard(netguard.Default())
+	err := get(t, ts.URL)
+	utils.SetNetworkGuard(testnet.LoopbackGuard())
+	assert.ErrorIs(t, err, netguard.ErrBlocked, "a pooled connection must not bypass the new guard")
+
+	require.NoError(t, get(t, ts.URL))
+	assert.Equal(t, int64(2), conns.Load())
+}
+
+func TestSetTransportSettings(t *testing.T) {
+	previous := utils.CurrentTransportSettings()
+	defer utils.SetTransportSettings(previous)
+
+	settings := utils.DefaultTransportSettings()
+	settings.ResponseHeaderTimeout = 50 * time.Millisecond
+	settings.DisableHTTP2 = true
+	utils.SetTransportSettings(settings)
+	assert.Equal(t, settings, utils.CurrentTransportSettings())
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+
+	err := get(t, slow.URL)
+	assert.ErrorContains(t, err, "timeout awaiting response headers")
+}
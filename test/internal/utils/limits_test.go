@@ -0,0 +1,133 @@
+package utils_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/utils"
+)
+
+func TestLimitedReader(t *testing.T) {
+	t.Run("Within Limit", func(t *testing.T) {
+		r := &utils.LimitedReader{Reader: strings.NewReader("hello"), Limit: 5}
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+		assert.False(t, r.Exceeded)
+	})
+
+	t.Run("Over Limit", func(t *testing.T) {
+		r := &utils.LimitedReader{Reader: strings.NewReader("hello world"), Limit: 5}
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+		assert.True(t, r.Exceeded)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		r := &utils.LimitedReader{Reader: strings.NewReader("hello world"), Limit: -1}
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(data))
+		assert.False(t, r.Exceeded)
+	})
+}
+
+func TestTraverseHTMLLimits(t *testing.T) {
+	var links strings.Builder
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&links, `<a href="/page-%d">link</a>`, i)
+	}
+	nested := strings.Repeat("<div>", 50) + "<h1>deep</h1>" + strings.Repeat("</div>", 50)
+
+	testCases := []struct {
+		name        string
+		content     string
+		limits      models.ParseLimits
+		truncated   bool
+		reason      string
+		linksOut    int
+		headingsOut int
+	}{
+		{"No Limits", links.String() + nested, models.ParseLimits{}, false, "", 10, 1},
+		{"Max Links", links.String(), models.ParseLimits{MaxLinks: 3}, true, "exceeded 3 links", 3, 0},
+		{"Max Depth", nested, models.ParseLimits{MaxDepth: 10}, true, "depth 10", 0, 0},
+		{"Max Nodes", links.String(), models.ParseLimits{MaxNodes: 5}, true, "exceeded 5 element nodes", 2, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader("<html><body>" + tc.content + "</body></html>"))
+			require.NoError(t, err)
+
+			analysis := &models.PageAnalysis{Headings: make(map[string]int)}
+			linksChan := make(chan models.LinkInfo, 100)
+			utils.TraverseHTML(doc, analysis, "https://example.com", linksChan, utils.TraverseOptions{Limits: tc.limits})
+			close(linksChan)
+
+			assert.Equal(t, tc.truncated, analysis.Truncated)
+			assert.Contains(t, analysis.TruncatedReason, tc.reason)
+			assert.Len(t, linksChan, tc.linksOut)
+			assert.Equal(t, tc.headingsOut, analysis.Headings["h1"])
+		})
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	countNodes := func(doc *html.Node) (nodes, depth int) {
+		var walk func(n *html.Node, d int)
+		walk = func(n *html.Node, d int) {
+			if n.Type == html.ElementNode {
+				nodes++
+				depth = max(depth, d)
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c, d+1)
+			}
+		}
+		walk(doc, 0)
+		return nodes, depth
+	}
+
+	t.Run("Within Limits", func(t *testing.T) {
+		const page = `<!DOCTYPE html><html><head><title>t</title><script>if (a<b) {}</script></head>` +
+			`<body><ul><li>one<li>two</ul><p>a<p>b<br><img src="x"></body></html>`
+		doc, reason, err := utils.ParseHTML(strings.NewReader(page), models.ParseLimits{MaxNodes: 100, MaxDepth: 5})
+		require.NoError(t, err)
+		assert.Empty(t, reason)
+
+		want, err := html.Parse(strings.NewReader(page))
+		require.NoError(t, err)
+		var got, expected strings.Builder
+		require.NoError(t, html.Render(&got, doc))
+		require.NoError(t, html.Render(&expected, want))
+		assert.Equal(t, expected.String(), got.String())
+	})
+
+	t.Run("Max Nodes", func(t *testing.T) {
+		page := strings.Repeat("<p>paragraph</p>", 100_000)
+		doc, reason, err := utils.ParseHTML(strings.NewReader(page), models.ParseLimits{MaxNodes: 1000})
+		require.NoError(t, err)
+		assert.Equal(t, "document exceeded 1000 element nodes", reason)
+
+		nodes, _ := countNodes(doc)
+		assert.LessOrEqual(t, nodes, 1003, "only html, head and body are added by the parser")
+	})
+
+	t.Run("Max Depth", func(t *testing.T) {
+		page := strings.Repeat("<div>", 100_000)
+		doc, reason, err := utils.ParseHTML(strings.NewReader(page), models.ParseLimits{MaxDepth: 64})
+		require.NoError(t, err)
+		assert.Equal(t, "document nesting exceeded depth 64", reason)
+
+		_, depth := countNodes(doc)
+		assert.LessOrEqual(t, depth, 64+3)
+	})
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/utils"
+)
+
+func TestLinkFilter(t *testing.T) {
+	t.Run("No Patterns", func(t *testing.T) {
+		f, err := utils.NewLinkFilter(nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, f)
+		assert.True(t, f.Allows("https://example.com/anything"))
+	})
+
+	t.Run("Include And Exclude", func(t *testing.T) {
+		f, err := utils.NewLinkFilter([]string{`^https://example\.com/`}, []string{`\.pdf$`})
+		require.NoError(t, err)
+
+		assert.True(t, f.Allows("https://example.com/docs"))
+		assert.False(t, f.Allows("https://example.com/manual.pdf"), "exclude wins over include")
+		assert.False(t, f.Allows("https://other.example/docs"))
+	})
+
+	t.Run("Exclude Only", func(t *testing.T) {
+		f, err := utils.NewLinkFilter(nil, []string{"facebook|twitter"})
+		require.NoError(t, err)
+
+		assert.True(t, f.Allows("https://example.com/"))
+		assert.False(t, f.Allows("https://twitter.com/example"))
+	})
+
+	t.Run("Invalid Pattern", func(t *testing.T) {
+		_, err := utils.NewLinkFilter(nil, []string{"("})
+		assert.ErrorContains(t, err, "invalid exclude pattern")
+	})
+}
@@ -0,0 +1,15 @@
+package utils_test
+
+import (
+	"os"
+	"testing"
+
+	"web-analyzer/test/internal/testnet"
+)
+
+// TestMain lets link checks reach httptest servers, which listen on loopback
+// addresses that the default network guard blocks.
+func TestMain(m *testing.M) {
+	testnet.AllowLoopback()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,97 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/utils"
+)
+
+const mixedContentPage = `
+<!DOCTYPE html>
+<html>
+<head>
+	<script src="http://cdn.example.com/app.js"></script>
+	<script src="https://cdn.example.com/safe.js"></script>
+	<link rel="stylesheet" href="http://cdn.example.com/style.css">
+	<link rel="icon" href="http://cdn.example.com/favicon.ico">
+</head>
+<body>
+	<iframe src="http://widgets.example.com/frame"></iframe>
+	<img src="http://images.example.com/logo.png">
+	<img src="/relative.png" srcset="http://images.example.com/a.png 1x, https://images.example.com/b.png 2x">
+	<video src="https://media.example.com/v.mp4" poster="http://media.example.com/poster.jpg"></video>
+	<a href="http://example.com/insecure">Downgrade</a>
+	<a href="https://example.com/secure">Secure</a>
+</body>
+</html>
+`
+
+func traverse(t *testing.T, content, baseURL string) *models.PageAnalysis {
+	t.Helper()
+
+	doc, err := html.Parse(strings.NewReader(content))
+	require.NoError(t, err)
+
+	analysis := &models.PageAnalysis{
+		Headings:    make(map[string]int),
+		LinksStatus: make(map[string]string),
+	}
+	linksChan := make(chan models.LinkInfo, 100)
+	utils.TraverseHTML(doc, analysis, baseURL, linksChan, utils.TraverseOptions{})
+	close(linksChan)
+
+	return analysis
+}
+
+func TestMixedContent(t *testing.T) {
+	t.Run("HTTPS Page", func(t *testing.T) {
+		analysis := traverse(t, mixedContentPage, "https://example.com")
+
+		// script, stylesheet, iframe
+		assert.Equal(t, 3, analysis.MixedContent.Active)
+		// favicon, img src, img srcset, video poster
+		assert.Equal(t, 4, analysis.MixedContent.Passive)
+		assert.Equal(t, 1, analysis.MixedContent.DowngradeLinks)
+		assert.Len(t, analysis.MixedContent.Items, 8)
+
+		assert.Contains(t, analysis.MixedContent.Items, models.MixedContentItem{
+			Element:   "script",
+			Attribute: "src",
+			URL:       "http://cdn.example.com/app.js",
+			Type:      models.MixedContentActive,
+		})
+		assert.Contains(t, analysis.MixedContent.Items, models.MixedContentItem{
+			Element:   "a",
+			Attribute: "href",
+			URL:       "http://example.com/insecure",
+			Type:      models.MixedContentLinkDowngrade,
+		})
+	})
+
+	t.Run("Preload", func(t *testing.T) {
+		analysis := traverse(t, `<html><head>
+			<link rel="preload" as="image" href="http://cdn.example.com/hero.jpg">
+			<link rel="preload" as="video" href="http://cdn.example.com/intro.mp4">
+			<link rel="preload" as="script" href="http://cdn.example.com/app.js">
+			<link rel="preload" as="font" href="http://cdn.example.com/font.woff2">
+		</head></html>`, "https://example.com")
+
+		assert.Equal(t, 2, analysis.MixedContent.Passive)
+		assert.Equal(t, 2, analysis.MixedContent.Active)
+	})
+
+	t.Run("HTTP Page", func(t *testing.T) {
+		analysis := traverse(t, mixedContentPage, "http://example.com")
+
+		assert.Zero(t, analysis.MixedContent.Active)
+		assert.Zero(t, analysis.MixedContent.Passive)
+		assert.Zero(t, analysis.MixedContent.DowngradeLinks)
+		assert.Empty(t, analysis.MixedContent.Items)
+	})
+}
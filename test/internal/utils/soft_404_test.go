@@ -0,0 +1,50 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"web-analyzer/internal/utils"
+)
+
+func TestDetectSoft404(t *testing.T) {
+	article := "<p>" + strings.Repeat("A long article about search engines and how they crawl pages. ", 30) +
+		"Servers should answer missing pages with a real 404 Not Found status.</p>"
+
+	testCases := []struct {
+		name   string
+		html   string
+		soft   bool
+		reason string
+	}{
+		{"Title", `<html><head><title>Page Not Found | Example</title></head><body><p>Sorry.</p></body></html>`,
+			true, `title contains "not found"`},
+		{"Status In Title", `<html><head><title>Error 404</title></head><body></body></html>`, true, `title contains "404"`},
+		{"Heading", `<html><head><title>Example</title></head><body><h1>This page doesn't exist</h1></body></html>`,
+			true, `heading contains "page doesn't exist"`},
+		{"Short Body", `<html><head><title>Example</title></head><body><p>We couldn't find that page.</p></body></html>`,
+			true, `short page contains "couldn't find that page"`},
+		{"Script Text Ignored", `<html><head><title>Shop</title><script>var e = "not found";</script></head><body><p>Welcome</p></body></html>`,
+			false, ""},
+		{"Long Article", `<html><head><title>How crawlers work</title></head><body><h1>Crawling</h1>` + article + `</body></html>`,
+			false, ""},
+		{"Number In Text", `<html><head><title>Order 4040</title></head><body><p>Thanks for your order.</p></body></html>`,
+			false, ""},
+		{"Normal Page", `<html><head><title>Home</title></head><body><h1>Welcome</h1><p>Hello</p></body></html>`,
+			false, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tc.html))
+			require.NoError(t, err)
+			soft, reason := utils.DetectSoft404(doc)
+			assert.Equal(t, tc.soft, soft)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
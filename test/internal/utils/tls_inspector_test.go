@@ -0,0 +1,105 @@
+package utils_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/models"
+	"web-analyzer/internal/utils"
+)
+
+func TestInspectTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NotNil(t, resp.TLS)
+
+	t.Run("Matching Host", func(t *testing.T) {
+		info := utils.InspectTLS(resp.TLS, "127.0.0.1")
+		require.NotNil(t, info)
+
+		assert.True(t, strings.HasPrefix(info.Version, "TLS"))
+		assert.NotEmpty(t, info.CipherSuite)
+		require.NotEmpty(t, info.Certificates)
+		assert.Contains(t, info.Certificates[0].SANs, "127.0.0.1")
+		assert.Contains(t, info.Certificates[0].SANs, "example.com")
+		assert.True(t, info.SelfSigned)
+		assert.False(t, info.Expired)
+		assert.False(t, info.HostnameMismatch)
+		assert.Greater(t, info.DaysRemaining, 0)
+	})
+
+	t.Run("Hostname Mismatch", func(t *testing.T) {
+		info := utils.InspectTLS(resp.TLS, "other.test")
+		assert.True(t, info.HostnameMismatch)
+	})
+
+	t.Run("Plain HTTP", func(t *testing.T) {
+		assert.Nil(t, utils.InspectTLS(nil, "example.com"))
+	})
+}
+
+func TestCheckLinkExpiredCertificate(t *testing.T) {
+	cert := expiredCertificate(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	ts.StartTLS()
+	defer ts.Close()
+
+	analysis := &models.PageAnalysis{
+		LinksStatus: make(map[string]string),
+	}
+	link := models.LinkInfo{URL: ts.URL + "/expired", IsExternal: true, BaseURL: "https://example.com"}
+
+	utils.DefaultLinkChecker().Check(context.Background(), link, analysis)
+
+	assert.Equal(t, 1, analysis.BrokenLinks)
+	assert.True(t, strings.HasPrefix(analysis.LinksStatus[link.URL], utils.ExpiredCertStatusPrefix))
+}
+
+func expiredCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "expired.test"},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(-24 * time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
@@ -2,9 +2,14 @@ package utils_test
 
 import (
 	"bytes"
+	"context"
 	"golang.org/x/net/html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -55,7 +60,7 @@ func TestTraverseHTML(t *testing.T) {
 		}()
 
 		baseURL := "https://example.com"
-		utils.TraverseHTML(doc, analysis, baseURL, linksChan)
+		utils.TraverseHTML(doc, analysis, baseURL, linksChan, utils.TraverseOptions{})
 		close(linksChan)
 		wg.Wait()
 
@@ -128,7 +133,7 @@ func TestCheckLink(t *testing.T) {
 		}
 
 		initialBrokenLinks := analysis.BrokenLinks
-		utils.CheckLink(link, analysis)
+		utils.DefaultLinkChecker().Check(context.Background(), link, analysis)
 
 		assert.Equal(t, initialBrokenLinks, analysis.BrokenLinks)
 
@@ -138,9 +143,111 @@ func TestCheckLink(t *testing.T) {
 			BaseURL:    "https://example.com",
 		}
 
-		utils.CheckLink(link, analysis)
+		utils.DefaultLinkChecker().Check(context.Background(), link, analysis)
 
 		assert.Equal(t, initialBrokenLinks+1, analysis.BrokenLinks)
 		assert.Contains(t, analysis.LinksStatus[link.URL], "Error")
 	})
 }
+
+func TestCheckLinkWithoutStatusMap(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	analysis := &models.PageAnalysis{}
+	for range 2 { // a leaked lock used to block the second check
+		utils.DefaultLinkChecker().Check(context.Background(), models.LinkInfo{URL: ts.URL, BaseURL: ts.URL}, analysis)
+	}
+	assert.Equal(t, 2, analysis.BrokenLinks)
+}
+
+func TestLinkCheckerResult(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	checker := utils.DefaultLinkChecker()
+	ctx := context.Background()
+
+	result, ok := checker.Result(ctx, models.LinkInfo{URL: ts.URL + "/missing"})
+	require.True(t, ok)
+	assert.True(t, result.Broken)
+	assert.Equal(t, "Status: 404 Not Found", result.Status)
+
+	result, ok = checker.Result(ctx, models.LinkInfo{URL: ts.URL})
+	require.True(t, ok)
+	assert.False(t, result.Broken)
+	assert.Equal(t, "OK", result.Status)
+
+	_, ok = checker.Result(ctx, models.LinkInfo{URL: "mailto:team@example.com"})
+	assert.False(t, ok)
+}
+
+func TestLinkCheckerCheckContext(t *testing.T) {
+	started := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	checker := utils.DefaultLinkChecker()
+	checker.Timeout = time.Minute
+	link := models.LinkInfo{URL: ts.URL, BaseURL: ts.URL}
+
+	t.Run("Cancelled Before", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		analysis := &models.PageAnalysis{LinksStatus: make(map[string]string)}
+
+		assert.False(t, checker.Check(ctx, link, analysis))
+		assert.Empty(t, analysis.LinksStatus)
+	})
+
+	t.Run("Cancelled During", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+		analysis := &models.PageAnalysis{LinksStatus: make(map[string]string)}
+
+		start := time.Now()
+		assert.False(t, checker.Check(ctx, link, analysis))
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Zero(t, analysis.BrokenLinks, "an interrupted check is not a broken link")
+		assert.Empty(t, analysis.LinksStatus)
+	})
+}
+
+func TestTraverseHTMLContext(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><body><a href="/a">a</a><a href="/b">b</a><a href="/c">c</a></body></html>`))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	linksChan := make(chan models.LinkInfo) // unbuffered and read only once
+	go func() {
+		<-linksChan
+		cancel()
+	}()
+
+	analysis := &models.PageAnalysis{Headings: make(map[string]int)}
+	done := make(chan struct{})
+	go func() {
+		utils.TraverseHTML(doc, analysis, "https://example.com", linksChan, utils.TraverseOptions{
+			Sections: models.AllSections,
+			Context:  ctx,
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("traversal blocked on linksChan after cancellation")
+	}
+	assert.Equal(t, 3, analysis.InternalLinks)
+	assert.Equal(t, 2, analysis.SkippedLinks)
+}
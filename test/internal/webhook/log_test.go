@@ -0,0 +1,75 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/webhook"
+)
+
+func TestDeliveryLog(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer failing.Close()
+
+	sender, _ := newSender(3)
+	sender.Log = webhook.NewDeliveryLog(3)
+
+	t.Run("Pending Until Delivered", func(t *testing.T) {
+		delivery := sender.NewDelivery(ok.URL, "")
+		logged, found := sender.Log.Get("", delivery.ID)
+		require.True(t, found)
+		assert.Equal(t, webhook.StatusPending, logged.Status)
+		assert.Empty(t, logged.Attempts)
+
+		require.NoError(t, sender.Deliver(context.Background(), delivery, "test.event", "payload"))
+		logged, _ = sender.Log.Get("", delivery.ID)
+		assert.Equal(t, webhook.StatusDelivered, logged.Status)
+		assert.Equal(t, "test.event", logged.Event)
+		assert.Len(t, logged.Attempts, 1)
+	})
+
+	t.Run("List Newest First", func(t *testing.T) {
+		failed, err := sender.Send(context.Background(), failing.URL, "", "test.event", "payload")
+		require.Error(t, err)
+		delivered, err := sender.Send(context.Background(), ok.URL, "", "test.event", "payload")
+		require.NoError(t, err)
+
+		all := sender.Log.List("", "", 10)
+		require.Len(t, all, 3)
+		assert.Equal(t, delivered.ID, all[0].ID)
+		assert.Equal(t, failed.ID, all[1].ID)
+
+		onlyFailed := sender.Log.List("", webhook.StatusFailed, 10)
+		require.Len(t, onlyFailed, 1)
+		assert.Equal(t, http.StatusGone, onlyFailed[0].Attempts[0].StatusCode)
+
+		assert.Len(t, sender.Log.List("", "", 1), 1)
+	})
+
+	t.Run("Evicts Oldest", func(t *testing.T) {
+		oldest := sender.Log.List("", "", 10)[2]
+		_, err := sender.Send(context.Background(), ok.URL, "", "test.event", "payload")
+		require.NoError(t, err)
+
+		_, found := sender.Log.Get("", oldest.ID)
+		assert.False(t, found)
+		assert.Len(t, sender.Log.List("", "", 10), 3)
+	})
+
+	t.Run("Snapshots Are Not Shared", func(t *testing.T) {
+		delivery := sender.NewDelivery(ok.URL, "")
+		delivery.Attempts = append(delivery.Attempts, webhook.Attempt{At: time.Now()})
+		logged, _ := sender.Log.Get("", delivery.ID)
+		assert.Empty(t, logged.Attempts)
+	})
+}
@@ -0,0 +1,14 @@
+package webhook_test
+
+import (
+	"os"
+	"testing"
+
+	"web-analyzer/test/internal/testnet"
+)
+
+// Deliveries go through the outbound network guard; allow the loopback test receivers.
+func TestMain(m *testing.M) {
+	testnet.AllowLoopback()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,187 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/netguard"
+	"web-analyzer/internal/utils"
+	"web-analyzer/internal/webhook"
+)
+
+const secret = "s3cret"
+
+// receiver records the deliveries it accepts and fails the first failures requests.
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	status   int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.bodies = append(rc.bodies, body)
+	rc.headers = append(rc.headers, r.Header.Clone())
+	if len(rc.bodies) <= rc.failures {
+		w.WriteHeader(rc.status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newSender(attempts int) (*webhook.Sender, *[]time.Duration) {
+	sender := webhook.NewSender(secret, webhook.RetryPolicy{
+		MaxAttempts:    attempts,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	}, 5*time.Second)
+
+	var delays []time.Duration
+	sender.Sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return sender, &delays
+}
+
+func TestSend(t *testing.T) {
+	rc := &receiver{}
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	sender, _ := newSender(3)
+	delivery, err := sender.Send(context.Background(), ts.URL, "", "test.event", map[string]string{"hello": "world"})
+	require.NoError(t, err)
+
+	assert.Equal(t, webhook.StatusDelivered, delivery.Status)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Equal(t, http.StatusNoContent, delivery.Attempts[0].StatusCode)
+
+	require.Len(t, rc.bodies, 1)
+	assert.JSONEq(t, `{"hello":"world"}`, string(rc.bodies[0]))
+	header := rc.headers[0]
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "test.event", header.Get(webhook.HeaderEvent))
+	assert.Equal(t, delivery.ID, header.Get(webhook.HeaderID))
+	assert.NoError(t, webhook.Verify(secret, header, rc.bodies[0], time.Now(), time.Minute))
+}
+
+func TestSendRetries(t *testing.T) {
+	t.Run("Server Errors Are Retried With Backoff", func(t *testing.T) {
+		rc := &receiver{failures: 3, status: http.StatusServiceUnavailable}
+		ts := httptest.NewServer(rc)
+		defer ts.Close()
+
+		sender, delays := newSender(5)
+		delivery, err := sender.Send(context.Background(), ts.URL, "", "test.event", "payload")
+		require.NoError(t, err)
+
+		assert.Equal(t, webhook.StatusDelivered, delivery.Status)
+		assert.Len(t, delivery.Attempts, 4)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, *delays)
+
+		// Every attempt carries the same delivery id so receivers can deduplicate.
+		for _, header := range rc.headers {
+			assert.Equal(t, delivery.ID, header.Get(webhook.HeaderID))
+		}
+	})
+
+	t.Run("Gives Up After Max Attempts", func(t *testing.T) {
+		rc := &receiver{failures: 10, status: http.StatusBadGateway}
+		ts := httptest.NewServer(rc)
+		defer ts.Close()
+
+		sender, _ := newSender(3)
+		delivery, err := sender.Send(context.Background(), ts.URL, "", "test.event", "payload")
+		assert.ErrorIs(t, err, webhook.ErrDeliveryFailed)
+		assert.Equal(t, webhook.StatusFailed, delivery.Status)
+		require.Len(t, delivery.Attempts, 3)
+		assert.Equal(t, http.StatusBadGateway, delivery.Attempts[2].StatusCode)
+		assert.NotEmpty(t, delivery.Attempts[2].Error)
+	})
+
+	t.Run("Client Errors Are Not Retried", func(t *testing.T) {
+		rc := &receiver{failures: 10, status: http.StatusUnauthorized}
+		ts := httptest.NewServer(rc)
+		defer ts.Close()
+
+		sender, _ := newSender(5)
+		delivery, err := sender.Send(context.Background(), ts.URL, "", "test.event", "payload")
+		assert.ErrorIs(t, err, webhook.ErrDeliveryFailed)
+		assert.Len(t, delivery.Attempts, 1)
+	})
+
+	t.Run("Retry-After Is Honoured", func(t *testing.T) {
+		calls := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.Header().Set("Retry-After", "2")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		defer ts.Close()
+
+		sender, delays := newSender(3)
+		_, err := sender.Send(context.Background(), ts.URL, "", "test.event", "payload")
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{2 * time.Second}, *delays)
+	})
+
+	t.Run("Blocked Targets Are Not Retried", func(t *testing.T) {
+		previous := utils.NetworkGuard()
+		utils.SetNetworkGuard(netguard.Default())
+		defer utils.SetNetworkGuard(previous)
+
+		sender, _ := newSender(5)
+		delivery, err := sender.Send(context.Background(), "http://127.0.0.1:9/hook", "", "test.event", "payload")
+		assert.True(t, errors.Is(err, netguard.ErrBlocked))
+		assert.Len(t, delivery.Attempts, 1)
+	})
+
+	t.Run("Network Policy Reload", func(t *testing.T) {
+		ts := httptest.NewServer(&receiver{})
+		defer ts.Close()
+
+		sender, _ := newSender(1)
+		_, err := sender.Send(context.Background(), ts.URL, "", "test.event", "payload")
+		require.NoError(t, err)
+
+		previous := utils.NetworkGuard()
+		utils.SetNetworkGuard(netguard.Default())
+		defer utils.SetNetworkGuard(previous)
+
+		_, err = sender.Send(context.Background(), ts.URL, "", "test.event", "payload")
+		assert.ErrorIs(t, err, netguard.ErrBlocked, "a sender created before the reload follows the new policy")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"alerts":[]}`)
+	now := time.Unix(1_700_000_000, 0)
+	header := http.Header{}
+	header.Set(webhook.HeaderTimestamp, "1700000000")
+	header.Set(webhook.HeaderSignature, webhook.Sign(secret, "1700000000", body))
+
+	assert.NoError(t, webhook.Verify(secret, header, body, now, time.Minute))
+	assert.Error(t, webhook.Verify("other", header, body, now, time.Minute), "wrong secret")
+	assert.Error(t, webhook.Verify(secret, header, []byte(`{}`), now, time.Minute), "tampered body")
+	assert.Error(t, webhook.Verify(secret, header, body, now.Add(10*time.Minute), time.Minute), "stale timestamp")
+
+	header.Del(webhook.HeaderTimestamp)
+	assert.Error(t, webhook.Verify(secret, header, body, now, time.Minute))
+}
@@ -0,0 +1,214 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"web-analyzer/internal/analysis"
+	"web-analyzer/internal/auth"
+	"web-analyzer/internal/scheduler"
+	"web-analyzer/internal/server"
+	"web-analyzer/internal/storage"
+	"web-analyzer/internal/webhook"
+	"web-analyzer/pkg/client"
+	"web-analyzer/test/internal/testnet"
+)
+
+// newTestServer serves the API backed by an in-memory store and a site to analyze.
+func newTestServer(t *testing.T, authenticator *auth.Authenticator) (api, site *httptest.Server) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	testnet.AllowLoopbackFor(t)
+
+	site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Client</title></head><body><a href="/missing">gone</a></body></html>`))
+	}))
+	t.Cleanup(site.Close)
+
+	store := storage.NewMemoryStore(storage.Retention{})
+	analysis.SetResultStore(store)
+	t.Cleanup(func() { analysis.SetResultStore(nil) })
+
+	sched, err := scheduler.New(scheduler.Options{Analyze: analysis.AnalyzePage, Store: store})
+	require.NoError(t, err)
+	api = httptest.NewServer(server.SetupRouterWithOptions(server.Options{
+		Auth:       authenticator,
+		Scheduler:  sched,
+		Deliveries: webhook.NewDeliveryLog(10),
+	}))
+	t.Cleanup(api.Close)
+	return api, site
+}
+
+func TestClient(t *testing.T) {
+	api, site := newTestServer(t, nil)
+	c, err := client.New(api.URL+"/", client.Options{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	first, err := c.Analyze(ctx, client.AnalyzeRequest{URL: site.URL})
+	require.NoError(t, err)
+	assert.Equal(t, "Client", first.Title)
+	assert.Equal(t, 1, first.BrokenLinks)
+	require.NotEmpty(t, first.ResultID)
+
+	second, err := c.Analyze(ctx, client.AnalyzeRequest{URL: site.URL})
+	require.NoError(t, err)
+
+	t.Run("Export", func(t *testing.T) {
+		out, err := c.Export(ctx, client.AnalyzeRequest{URL: site.URL}, "csv")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(out), "page_url,link_url,state,status\n"))
+
+		out, err = c.ExportResult(ctx, first.ResultID, "sarif")
+		require.NoError(t, err)
+		assert.Contains(t, string(out), `"ruleId": "broken-link"`)
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		results, err := c.Batch(ctx, []client.AnalyzeRequest{{URL: site.URL}, {URL: site.URL, LinkTimeout: "soon"}})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.NotNil(t, results[0].Analysis)
+		assert.Equal(t, "Client", results[0].Analysis.Title)
+		assert.Nil(t, results[0].Error)
+		require.NotNil(t, results[1].Error)
+		assert.Equal(t, "invalid_input", results[1].Error.Code)
+
+		_, err = c.Batch(ctx, nil)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	})
+
+	t.Run("Results", func(t *testing.T) {
+		record, err := c.Result(ctx, first.ResultID)
+		require.NoError(t, err)
+		assert.Equal(t, site.URL, record.URL)
+
+		history, err := c.History(ctx, site.URL, 1, 0)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, history.Total, 2)
+		assert.Len(t, history.Results, 1)
+
+		report, err := c.Diff(ctx, first.ResultID, second.ResultID)
+		require.NoError(t, err)
+		assert.False(t, report.Changed)
+
+		_, err = c.Result(ctx, "unknown")
+		assert.True(t, client.IsNotFound(err))
+	})
+
+	t.Run("Schedules", func(t *testing.T) {
+		job, err := c.CreateSchedule(ctx, client.JobSpec{
+			URL:      site.URL,
+			Interval: "1h",
+			Rules:    []client.Rule{{Type: scheduler.RuleNewBrokenLinks}},
+		})
+		require.NoError(t, err)
+
+		jobs, err := c.Schedules(ctx)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, job.ID, jobs[0].ID)
+
+		run, err := c.RunSchedule(ctx, job.ID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, run.ResultID)
+
+		got, err := c.Schedule(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, run.ResultID, got.LastResultID)
+
+		require.NoError(t, c.DeleteSchedule(ctx, job.ID))
+		_, err = c.Schedule(ctx, job.ID)
+		assert.True(t, client.IsNotFound(err))
+	})
+
+	t.Run("Deliveries", func(t *testing.T) {
+		deliveries, err := c.Deliveries(ctx, webhook.StatusFailed, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+
+		_, err = c.Delivery(ctx, "unknown")
+		assert.True(t, client.IsNotFound(err))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := c.Analyze(ctx, client.AnalyzeRequest{URL: site.URL, LinkTimeout: "soon"})
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.NotEmpty(t, apiErr.RequestID)
+		require.Len(t, apiErr.Response.Fields, 1)
+		assert.Equal(t, "link_timeout", apiErr.Response.Fields[0].Field)
+		assert.Contains(t, err.Error(), "link_timeout")
+
+		_, err = c.Analyze(ctx, client.AnalyzeRequest{URL: site.URL, CallbackURL: "https://hooks.example.com"})
+		assert.ErrorContains(t, err, "AnalyzeAsync")
+	})
+}
+
+func TestClientAuthentication(t *testing.T) {
+	authenticator := auth.New(&auth.Config{Keys: []auth.APIKey{
+		{ID: "service", Key: "static-key"},
+		{ID: "ci", Secret: "signing-secret"},
+	}})
+	api, site := newTestServer(t, authenticator)
+	ctx := context.Background()
+
+	for name, opts := range map[string]client.Options{
+		"API Key":   {APIKey: "static-key"},
+		"Signature": {KeyID: "ci", Secret: "signing-secret"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := client.New(api.URL, opts)
+			require.NoError(t, err)
+			result, err := c.Analyze(ctx, client.AnalyzeRequest{URL: site.URL})
+			require.NoError(t, err)
+			assert.Equal(t, "Client", result.Title)
+		})
+	}
+
+	t.Run("Missing Credentials", func(t *testing.T) {
+		c, err := client.New(api.URL, client.Options{})
+		require.NoError(t, err)
+		_, err = c.Schedules(ctx)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	})
+}
+
+func TestNew(t *testing.T) {
+	_, err := client.New("localhost:8080", client.Options{})
+	assert.Error(t, err)
+
+	_, err = client.New("http://localhost:8080", client.Options{KeyID: "ci"})
+	assert.Error(t, err)
+}
+
+// TestClientDependencies keeps the client importable without the server: besides the
+// standard library it may only depend on pkg/api.
+func TestClientDependencies(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+	out, err := exec.Command(goTool, "list", "-deps", "-f", "{{if not .Standard}}{{.ImportPath}}{{end}}", "web-analyzer/pkg/client").Output()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"web-analyzer/pkg/api", "web-analyzer/pkg/client"}, strings.Fields(string(out)))
+}